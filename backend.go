@@ -0,0 +1,65 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloom
+
+import (
+	"context"
+	"iter"
+)
+
+// Chatter is implemented by backends that answer chat requests, either at once or as a stream.
+type Chatter interface {
+	Chat(ctx context.Context, req *Chat) (*ModelResponse, error)
+	ChatStream(ctx context.Context, req *Chat) iter.Seq2[*ModelResponse, error]
+}
+
+// Generator is implemented by backends that complete prompts, either at once or as a stream.
+type Generator interface {
+	Generate(ctx context.Context, req *PromptInfo) (*PromptResult, error)
+	GenerateStream(ctx context.Context, req *PromptInfo) iter.Seq2[*PromptResult, error]
+}
+
+// Embedder is implemented by backends that turn text into embedding vectors.
+type Embedder interface {
+	Embed(ctx context.Context, model, input string, options map[string]interface{}) (*EmbedResult, error)
+	EmbedBatch(ctx context.Context, model string, inputs []string, opts *EmbedBatchOptions) (*EmbedResult, error)
+}
+
+// ModelManager is implemented by backends that manage the models stored on a server.
+// The progress variants are used so a nil ProgressFunc covers the plain calls as well.
+type ModelManager interface {
+	ListModels(ctx context.Context) (*ModelList, error)
+	FetchModelInfo(ctx context.Context, model string, verbose bool) (*ModelInfoResult, error)
+	ProcessStatus(ctx context.Context) (*ModelProcessStatus, error)
+	PullModelProgress(ctx context.Context, model string, fn ProgressFunc) (*PullModelResult, error)
+	PushModelProgress(ctx context.Context, model string, fn ProgressFunc) (*PushModelResult, error)
+	CreateModelProgress(ctx context.Context, req *CreateModelRequest, fn ProgressFunc) (*CreateModelResult, error)
+	CopyModelProgress(ctx context.Context, source, destination string, fn ProgressFunc) (*CopyModelResult, error)
+	DeleteModel(ctx context.Context, req *DeleteModelRequest) (*DeleteModelResult, error)
+}
+
+// Backend combines every capability of an Ollama server. *Client implements it, and
+// Middleware values wrap it to add behaviour such as logging, caching, or fallback.
+type Backend interface {
+	Chatter
+	Generator
+	Embedder
+	ModelManager
+}
+
+var _ Backend = (*Client)(nil)
@@ -23,7 +23,6 @@ import (
 	"io"
 	"net/http"
 	"net/url"
-	"path"
 	"strings"
 )
 
@@ -40,15 +39,15 @@ func (c *Client) CheckBlobExists(
 	ctx context.Context,
 	digest string,
 ) (bool, error) {
-	if strings.Contains(digest, "/") || strings.Contains(digest, "..") {
-		return false, fmt.Errorf("invalid digest: %s", digest)
+	if err := validateDigest(digest); err != nil {
+		return false, err
 	}
 
 	safeDigest := url.PathEscape(digest)
 	rel := &url.URL{Path: "/api/blobs/" + safeDigest}
-	u := c.BaseURL.ResolveReference(rel)
+	u := c.resolve(rel)
 
-	req, err := http.NewRequestWithContext(
+	req, err := c.newRequest(
 		ctx,
 		"HEAD",
 		u.String(),
@@ -59,7 +58,7 @@ func (c *Client) CheckBlobExists(
 		return false, err
 	}
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return false, err
 	}
@@ -73,31 +72,52 @@ func (c *Client) CheckBlobExists(
 		return false, nil
 
 	default:
-		return false, fmt.Errorf(
-			"unexpected status code: %d",
-			resp.StatusCode,
-		)
+		return false, newAPIError(resp)
 	}
 }
 
 // PushBlob uploads a blob to the server.
 // It sends a POST request with the blob data to the /api/blobs/{digest} endpoint.
+// The digest is not verified locally; UploadFile computes it from the file instead.
 // Parameters:
 //   - ctx: A context to control request lifetime (e.g., cancellation).
 //   - digest: A string representing the blob's digest or identifier.
 //   - file: An io.Reader that provides the blob's data.
 //
 // Returns:
-//   - An error if the upload fails.
+//   - An error if the digest is invalid or the upload fails.
 func (c *Client) PushBlob(
 	ctx context.Context,
 	digest string,
 	file io.Reader,
 ) error {
-	rel := &url.URL{Path: path.Join("/api/blobs", digest)}
-	u := c.BaseURL.ResolveReference(rel)
+	return c.pushBlob(ctx, digest, file, -1)
+}
+
+// pushBlob uploads a blob like PushBlob, announcing its size when known.
+// Parameters:
+//   - ctx: A context to control request lifetime (e.g., cancellation).
+//   - digest: A string representing the blob's digest or identifier.
+//   - file: An io.Reader that provides the blob's data.
+//   - size: The number of bytes file provides, or -1 if unknown.
+//
+// Returns:
+//   - An error if the digest is invalid or the upload fails.
+func (c *Client) pushBlob(
+	ctx context.Context,
+	digest string,
+	file io.Reader,
+	size int64,
+) error {
+	if err := validateDigest(digest); err != nil {
+		return err
+	}
 
-	req, err := http.NewRequestWithContext(
+	safeDigest := url.PathEscape(digest)
+	rel := &url.URL{Path: "/api/blobs/" + safeDigest}
+	u := c.resolve(rel)
+
+	req, err := c.newRequest(
 		ctx,
 		"POST",
 		u.String(),
@@ -108,8 +128,12 @@ func (c *Client) PushBlob(
 		return err
 	}
 
+	if size >= 0 {
+		req.ContentLength = size
+	}
+
 	req.Header.Set("Content-Type", "application/octet-stream")
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.do(req)
 
 	if err != nil {
 		return err
@@ -117,12 +141,22 @@ func (c *Client) PushBlob(
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
 		return fmt.Errorf(
-			"failed to push blob: %s",
-			string(body),
+			"failed to push blob: %w",
+			newAPIError(resp),
 		)
 	}
 
 	return nil
 }
+
+// validateDigest rejects digests that are empty or could escape the /api/blobs/ path.
+func validateDigest(digest string) error {
+	if digest == "" ||
+		strings.Contains(digest, "/") ||
+		strings.Contains(digest, "..") {
+		return fmt.Errorf("invalid digest: %s", digest)
+	}
+
+	return nil
+}
@@ -33,8 +33,10 @@ type Message struct {
 
 	// Images contains an optional list of image URLs associated with the message.
 	Images []string `json:"images,omitempty"`
-	// ToolCalls holds an optional list of tool call details, where each tool call is represented as a map.
-	ToolCalls []map[string]interface{} `json:"tool_calls,omitempty"`
+	// ToolCalls holds the tools the assistant asked to invoke, if any.
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+	// ToolName names the tool whose result a message with the "tool" role carries.
+	ToolName string `json:"tool_name,omitempty"`
 }
 
 // Chat encapsulates the data required to initiate a conversation with the language model.
@@ -49,9 +51,10 @@ type Chat struct {
 	// It can be specified as a string or a more complex structure.
 	Format interface{} `json:"format,omitempty"`
 	// Options provides additional optional settings as key-value pairs for the chat session.
+	// ModelOptions.ToMap produces a typed, validated value for this field.
 	Options map[string]interface{} `json:"options,omitempty"`
-	// Tools lists optional tools (as maps) that may be utilized during the conversation.
-	Tools []map[string]interface{} `json:"tools,omitempty"`
+	// Tools lists optional tools that the model may ask to invoke during the conversation.
+	Tools []Tool `json:"tools,omitempty"`
 
 	// Stream is an optional flag that, when set to true, requests the server to stream the response.
 	Stream *bool `json:"stream,omitempty"`
@@ -100,7 +103,7 @@ func (c *Client) Chat(
 	req *Chat,
 ) (*ModelResponse, error) {
 	rel := &url.URL{Path: "/api/chat"}
-	u := c.BaseURL.ResolveReference(rel)
+	u := c.resolve(rel)
 
 	return c.sendChatRequest(
 		ctx,
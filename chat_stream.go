@@ -0,0 +1,135 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloom
+
+import (
+	"context"
+	"iter"
+	"net/url"
+)
+
+// ChatStream sends a chat request to the Ollama server and streams the model's reply chunk by chunk.
+// The request is always sent with streaming enabled; the caller's Chat value is left untouched.
+// Iteration stops after the final chunk, on the first error, or when the caller breaks out of the loop,
+// in which case the underlying connection is closed.
+// Parameters:
+//   - ctx: A context for controlling cancellation and timeouts for the request.
+//   - req: A pointer to a Chat struct containing the conversation history and optional configuration.
+//
+// Returns:
+//   - An iterator yielding each ModelResponse chunk, or an error that ended the stream.
+func (c *Client) ChatStream(
+	ctx context.Context,
+	req *Chat,
+) iter.Seq2[*ModelResponse, error] {
+	return func(yield func(*ModelResponse, error) bool) {
+		stream := true
+		streamReq := *req
+		streamReq.Stream = &stream
+
+		rel := &url.URL{Path: "/api/chat"}
+		u := c.resolve(rel)
+
+		resp, err := c.sendStreamRequest(
+			ctx,
+			"POST",
+			u.String(),
+			&streamReq,
+		)
+
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer resp.Body.Close()
+
+		for chunk, err := range decodeStream[ModelResponse](ctx, resp) {
+			if !yield(chunk, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// ChatStreamFunc streams a chat reply like ChatStream, invoking fn for every chunk as it arrives.
+// The chunks are accumulated into a single ModelResponse carrying the full message and the timing
+// fields of the final chunk. If the context is cancelled or fn returns an error, the partial
+// response gathered so far is returned together with that error.
+// Parameters:
+//   - ctx: A context for controlling cancellation and timeouts for the request.
+//   - req: A pointer to a Chat struct containing the conversation history and optional configuration.
+//   - fn: An optional callback receiving each chunk; a non-nil error aborts the stream.
+//
+// Returns:
+//   - A pointer to the accumulated ModelResponse, which is never nil.
+//   - An error if the request, the stream, or the callback fails.
+func (c *Client) ChatStreamFunc(
+	ctx context.Context,
+	req *Chat,
+	fn func(*ModelResponse) error,
+) (*ModelResponse, error) {
+	acc := &ModelResponse{}
+	for chunk, err := range c.ChatStream(ctx, req) {
+		if err != nil {
+			return acc, err
+		}
+
+		acc.merge(chunk)
+		if fn != nil {
+			if err := fn(chunk); err != nil {
+				return acc, err
+			}
+		}
+	}
+
+	return acc, nil
+}
+
+// merge folds a streamed chunk into the accumulated response. Message content and tool calls
+// are appended, while metadata and timing fields are taken from the latest chunk that sets them.
+func (r *ModelResponse) merge(chunk *ModelResponse) {
+	if chunk.Model != "" {
+		r.Model = chunk.Model
+	}
+
+	if !chunk.CreatedAt.IsZero() {
+		r.CreatedAt = chunk.CreatedAt
+	}
+
+	if chunk.Message.Role != "" {
+		r.Message.Role = chunk.Message.Role
+	}
+
+	r.Message.Content += chunk.Message.Content
+	r.Message.Images = append(r.Message.Images, chunk.Message.Images...)
+	r.Message.ToolCalls = append(r.Message.ToolCalls, chunk.Message.ToolCalls...)
+
+	r.Done = chunk.Done
+	if chunk.DoneReason != "" {
+		r.DoneReason = chunk.DoneReason
+	}
+
+	if chunk.Done {
+		r.TotalDuration = chunk.TotalDuration
+		r.LoadDuration = chunk.LoadDuration
+		r.PromptEvalCount = chunk.PromptEvalCount
+		r.PromptEvalDuration = chunk.PromptEvalDuration
+		r.EvalCount = chunk.EvalCount
+		r.EvalDuration = chunk.EvalDuration
+	}
+}
@@ -18,8 +18,13 @@
 package golloom
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 )
 
@@ -32,6 +37,11 @@ type Client struct {
 	// HTTPClient is the HTTP client used to make requests. Its configuration (e.g., timeout)
 	// can be set during client initialization.
 	HTTPClient *http.Client
+	// Header holds default headers, such as Authorization or User-Agent, that are added
+	// to every request the client sends.
+	Header http.Header
+	// RetryPolicy controls how transient failures are retried. A nil policy disables retries.
+	RetryPolicy *RetryPolicy
 }
 
 // NewClient creates a new instance of Client configured to communicate with the Ollama server.
@@ -47,15 +57,127 @@ func NewClient(
 	baseURL string,
 	minutes time.Duration,
 ) (*Client, error) {
-	parsed, err := url.Parse(baseURL)
+	return NewClientWithOptions(
+		WithBaseURL(baseURL),
+		WithTimeout(minutes*time.Minute),
+	)
+}
+
+// NewClientWithOptions creates a new instance of Client and applies the given options in order.
+// Without options the client targets "http://localhost:11434" with an HTTP client that has no
+// timeout, which suits long-running streamed responses.
+// Parameters:
+//   - opts: A list of Option values configuring the base URL, HTTP client, headers, and so on.
+//
+// Returns:
+//   - A pointer to a Client instance configured by the options.
+//   - An error if any option fails to apply.
+func NewClientWithOptions(opts ...Option) (*Client, error) {
+	c := &Client{
+		BaseURL: &url.URL{
+			Scheme: "http",
+			Host:   "localhost:11434",
+		},
+		HTTPClient: &http.Client{},
+		Header:     make(http.Header),
+	}
+
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// newRequest is the shared request builder used for every call the Client makes.
+// It creates the request with the given context and applies the client's default headers.
+// Parameters:
+//   - ctx: A context.Context for managing request deadlines and cancellations.
+//   - method: The HTTP method to use for the request.
+//   - urlStr: The target URL as a string.
+//   - body: An optional io.Reader providing the request body.
+//
+// Returns:
+//   - A pointer to the prepared http.Request.
+//   - An error if the request cannot be created.
+func (c *Client) newRequest(
+	ctx context.Context,
+	method, urlStr string,
+	body io.Reader,
+) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(
+		ctx,
+		method,
+		urlStr,
+		body,
+	)
+
 	if err != nil {
 		return nil, err
 	}
 
-	return &Client{
-		BaseURL: parsed,
-		HTTPClient: &http.Client{
-			Timeout: minutes * time.Minute,
-		},
-	}, nil
+	for key, values := range c.Header {
+		req.Header[key] = append([]string(nil), values...)
+	}
+
+	return req, nil
+}
+
+// newJSONRequest builds a request through newRequest with body encoded as JSON
+// and the Content-Type header set accordingly.
+// Parameters:
+//   - ctx: A context.Context for managing request deadlines and cancellations.
+//   - method: The HTTP method to use for the request.
+//   - urlStr: The target URL as a string.
+//   - body: The payload to be sent with the request; it will be JSON-encoded.
+//
+// Returns:
+//   - A pointer to the prepared http.Request.
+//   - An error if the body cannot be encoded or the request cannot be created.
+func (c *Client) newJSONRequest(
+	ctx context.Context,
+	method, urlStr string,
+	body interface{},
+) (*http.Request, error) {
+	buf := new(bytes.Buffer)
+	if err := json.NewEncoder(buf).Encode(body); err != nil {
+		return nil, err
+	}
+
+	req, err := c.newRequest(
+		ctx,
+		method,
+		urlStr,
+		buf,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// resolve resolves an API reference such as "/api/chat" against the client's base URL.
+// Unlike url.URL.ResolveReference, any path on the base URL is kept as a prefix, so
+// servers exposed under a sub-path of a reverse proxy are reached correctly.
+func (c *Client) resolve(rel *url.URL) *url.URL {
+	base := *c.BaseURL
+	base.Path = strings.TrimSuffix(base.Path, "/")
+
+	if base.RawPath != "" {
+		base.RawPath = strings.TrimSuffix(base.RawPath, "/")
+	}
+
+	ref := *rel
+	ref.Path = base.Path + rel.Path
+
+	if rel.RawPath != "" || base.RawPath != "" {
+		ref.RawPath = base.EscapedPath() + rel.EscapedPath()
+	}
+
+	return base.ResolveReference(&ref)
 }
@@ -0,0 +1,420 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Conversation manages a multi-turn chat with a model on top of Client.Chat. It owns the system
+// prompt, message history, model, options, and keep-alive setting, and is safe for concurrent use;
+// turns sent concurrently are processed one at a time. A Conversation can be saved to and loaded
+// from JSON so a session survives a restart, and forked to explore alternative replies.
+type Conversation struct {
+	client *Client
+
+	sendMu sync.Mutex
+	mu     sync.RWMutex
+
+	model     string
+	system    string
+	messages  []Message
+	options   map[string]interface{}
+	keepAlive string
+
+	summary          string
+	historyPolicy    *HistoryPolicy
+	contextLengths   map[string]int
+	lastPromptTokens int
+	lastPromptChars  int
+}
+
+// conversationState is the JSON representation of a Conversation.
+type conversationState struct {
+	Model     string                 `json:"model"`
+	System    string                 `json:"system,omitempty"`
+	Messages  []Message              `json:"messages"`
+	Options   map[string]interface{} `json:"options,omitempty"`
+	KeepAlive string                 `json:"keep_alive,omitempty"`
+	Summary   string                 `json:"summary,omitempty"`
+}
+
+// NewConversation creates an empty Conversation with the given model and system prompt.
+// Parameters:
+//   - client: The Client used to send chat requests.
+//   - model: The model that answers the conversation.
+//   - system: An optional system prompt sent ahead of the history on every turn.
+//
+// Returns:
+//   - A pointer to the new Conversation.
+func NewConversation(
+	client *Client,
+	model, system string,
+) *Conversation {
+	return &Conversation{
+		client: client,
+		model:  model,
+		system: system,
+	}
+}
+
+// Model returns the model that answers the conversation.
+func (cv *Conversation) Model() string {
+	cv.mu.RLock()
+	defer cv.mu.RUnlock()
+
+	return cv.model
+}
+
+// SetModel changes the model used for subsequent turns.
+func (cv *Conversation) SetModel(model string) {
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+
+	cv.model = model
+}
+
+// System returns the system prompt of the conversation.
+func (cv *Conversation) System() string {
+	cv.mu.RLock()
+	defer cv.mu.RUnlock()
+
+	return cv.system
+}
+
+// SetSystem changes the system prompt sent ahead of the history on subsequent turns.
+func (cv *Conversation) SetSystem(system string) {
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+
+	cv.system = system
+}
+
+// SetOptions sets the model options sent with every turn (see ModelOptions.ToMap).
+func (cv *Conversation) SetOptions(options map[string]interface{}) {
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+
+	cv.options = copyOptions(options)
+}
+
+// SetKeepAlive sets how long the model stays loaded after each turn, e.g. "10m".
+func (cv *Conversation) SetKeepAlive(keepAlive string) {
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+
+	cv.keepAlive = keepAlive
+}
+
+// Messages returns a copy of the message history, excluding the system prompt.
+func (cv *Conversation) Messages() []Message {
+	cv.mu.RLock()
+	defer cv.mu.RUnlock()
+
+	return append([]Message(nil), cv.messages...)
+}
+
+// Append adds messages to the history without sending anything to the model.
+func (cv *Conversation) Append(messages ...Message) {
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+
+	cv.messages = append(cv.messages, messages...)
+}
+
+// Reset clears the message history and any history summary while keeping the model,
+// system prompt, and options.
+func (cv *Conversation) Reset() {
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+
+	cv.messages = nil
+	cv.summary = ""
+}
+
+// Send adds a user message to the conversation, asks the model for a reply, and appends the reply
+// to the history. If the request fails, the user message is removed again and any history evicted
+// for the turn is restored, so the turn can be retried.
+// Parameters:
+//   - ctx: A context for controlling cancellation and timeouts for the request.
+//   - content: The text of the user's message.
+//
+// Returns:
+//   - A pointer to the ModelResponse carrying the model's reply.
+//   - An error if the chat request fails.
+func (cv *Conversation) Send(
+	ctx context.Context,
+	content string,
+) (*ModelResponse, error) {
+	return cv.SendMessage(ctx, Message{
+		Role:    "user",
+		Content: content,
+	})
+}
+
+// SendMessage is like Send but takes a complete message, e.g. one carrying images.
+func (cv *Conversation) SendMessage(
+	ctx context.Context,
+	msg Message,
+) (*ModelResponse, error) {
+	return cv.turn(ctx, msg, func(req *Chat) (*ModelResponse, error) {
+		return cv.client.Chat(ctx, req)
+	})
+}
+
+// SendStreamFunc is like Send but streams the reply, invoking fn for every chunk as it arrives.
+// If the stream is interrupted, the partial reply is discarded along with the user message.
+func (cv *Conversation) SendStreamFunc(
+	ctx context.Context,
+	content string,
+	fn func(*ModelResponse) error,
+) (*ModelResponse, error) {
+	msg := Message{
+		Role:    "user",
+		Content: content,
+	}
+
+	return cv.turn(ctx, msg, func(req *Chat) (*ModelResponse, error) {
+		return cv.client.ChatStreamFunc(ctx, req, fn)
+	})
+}
+
+// turn runs a single exchange: it records msg, applies the history policy, builds the request
+// from the current state, sends it with send, and records the reply. On failure the history and
+// summary are restored to their state before the turn, undoing any eviction by the policy.
+func (cv *Conversation) turn(
+	ctx context.Context,
+	msg Message,
+	send func(*Chat) (*ModelResponse, error),
+) (*ModelResponse, error) {
+	cv.sendMu.Lock()
+	defer cv.sendMu.Unlock()
+
+	cv.mu.Lock()
+	index := len(cv.messages)
+	cv.messages = append(cv.messages, msg)
+	snapshot := append([]Message(nil), cv.messages...)
+	summary := cv.summary
+	policy := cv.historyPolicy
+	cv.mu.Unlock()
+
+	var resp *ModelResponse
+	err := func() error {
+		if policy != nil {
+			if err := cv.fitHistory(ctx, policy); err != nil {
+				return err
+			}
+		}
+
+		cv.mu.RLock()
+		req := cv.request()
+		cv.mu.RUnlock()
+
+		var err error
+		resp, err = send(req)
+		if err == nil {
+			cv.mu.Lock()
+			cv.recordPromptSize(req, resp)
+			cv.mu.Unlock()
+		}
+
+		return err
+	}()
+
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+
+	if err != nil {
+		cv.messages = append(snapshot[:index], snapshot[index+1:]...)
+		cv.summary = summary
+
+		return nil, err
+	}
+
+	cv.messages = append(cv.messages, resp.Message)
+	return resp, nil
+}
+
+// request builds the Chat for the current state. The caller must hold cv.mu.
+func (cv *Conversation) request() *Chat {
+	return &Chat{
+		Model:     cv.model,
+		Messages:  append(cv.pinned(), cv.messages...),
+		Options:   copyOptions(cv.options),
+		KeepAlive: cv.keepAlive,
+	}
+}
+
+// pinned returns the messages sent ahead of the history on every turn: the system prompt
+// and the summary of evicted turns, when present. The caller must hold cv.mu.
+func (cv *Conversation) pinned() []Message {
+	var messages []Message
+	if cv.system != "" {
+		messages = append(messages, Message{
+			Role:    "system",
+			Content: cv.system,
+		})
+	}
+
+	if cv.summary != "" {
+		messages = append(messages, Message{
+			Role:    "system",
+			Content: "Summary of the earlier conversation:\n" + cv.summary,
+		})
+	}
+
+	return messages
+}
+
+// Fork creates an independent copy of the conversation holding only the first index messages,
+// so an alternative reply can be explored from that point. Fork(len(Messages())) copies everything.
+// Parameters:
+//   - index: The number of leading messages kept in the fork.
+//
+// Returns:
+//   - A pointer to the new Conversation, sharing the client but no mutable state.
+//   - An error if index is out of range.
+func (cv *Conversation) Fork(index int) (*Conversation, error) {
+	cv.mu.RLock()
+	defer cv.mu.RUnlock()
+
+	if index < 0 || index > len(cv.messages) {
+		return nil, fmt.Errorf(
+			"fork index %d out of range [0, %d]",
+			index,
+			len(cv.messages),
+		)
+	}
+
+	return &Conversation{
+		client:    cv.client,
+		model:     cv.model,
+		system:    cv.system,
+		messages:  append([]Message(nil), cv.messages[:index]...),
+		options:   copyOptions(cv.options),
+		keepAlive: cv.keepAlive,
+
+		summary:       cv.summary,
+		historyPolicy: cv.historyPolicy,
+	}, nil
+}
+
+// MarshalJSON encodes the conversation's model, system prompt, history, options, and keep-alive.
+func (cv *Conversation) MarshalJSON() ([]byte, error) {
+	cv.mu.RLock()
+	defer cv.mu.RUnlock()
+
+	return json.Marshal(conversationState{
+		Model:     cv.model,
+		System:    cv.system,
+		Messages:  cv.messages,
+		Options:   cv.options,
+		KeepAlive: cv.keepAlive,
+		Summary:   cv.summary,
+	})
+}
+
+// UnmarshalJSON restores the state encoded by MarshalJSON, keeping the conversation's client.
+func (cv *Conversation) UnmarshalJSON(data []byte) error {
+	var state conversationState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+
+	cv.model = state.Model
+	cv.system = state.System
+	cv.messages = state.Messages
+	cv.options = state.Options
+	cv.keepAlive = state.KeepAlive
+	cv.summary = state.Summary
+
+	return nil
+}
+
+// Save writes the conversation to path as JSON. The file is written to a temporary
+// file in the same directory first and then renamed, so a crash never leaves it truncated.
+func (cv *Conversation) Save(path string) error {
+	data, err := json.MarshalIndent(cv, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// LoadConversation reads a conversation previously written by Save.
+// Parameters:
+//   - client: The Client used to send chat requests for the restored conversation.
+//   - path: The path of the JSON file.
+//
+// Returns:
+//   - A pointer to the restored Conversation.
+//   - An error if the file cannot be read or decoded.
+func LoadConversation(
+	client *Client,
+	path string,
+) (*Conversation, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cv := &Conversation{client: client}
+	if err := json.Unmarshal(data, cv); err != nil {
+		return nil, fmt.Errorf("decoding conversation %s: %w", path, err)
+	}
+
+	return cv, nil
+}
+
+// copyOptions returns a shallow copy of an options map, or nil for an empty one.
+func copyOptions(options map[string]interface{}) map[string]interface{} {
+	if len(options) == 0 {
+		return nil
+	}
+
+	out := make(map[string]interface{}, len(options))
+	for k, v := range options {
+		out[k] = v
+	}
+
+	return out
+}
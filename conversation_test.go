@@ -0,0 +1,114 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloom_test
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nthnn/golloom"
+	"github.com/nthnn/golloom/golloomtest"
+)
+
+func TestConversationSend(t *testing.T) {
+	srv := golloomtest.NewServer()
+	defer srv.Close()
+
+	cv := golloom.NewConversation(srv.Client(), "llama3", "Be brief.")
+	ctx := context.Background()
+
+	if _, err := cv.Send(ctx, "first"); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := cv.Send(ctx, "second")
+	if err != nil || resp.Message.Content != "second" {
+		t.Fatalf("reply = %+v, %v", resp, err)
+	}
+
+	if got := len(cv.Messages()); got != 4 {
+		t.Errorf("history holds %d messages, want 4", got)
+	}
+
+	last, _ := srv.LastRequest("/api/chat")
+
+	var sent golloom.Chat
+	if err := last.Decode(&sent); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sent.Messages) != 4 || sent.Messages[0].Role != "system" || sent.Messages[0].Content != "Be brief." {
+		t.Errorf("sent messages = %+v, want the system prompt and three turns", sent.Messages)
+	}
+}
+
+func TestConversationFailedTurnRestoresHistory(t *testing.T) {
+	srv := golloomtest.NewServer()
+	defer srv.Close()
+
+	cv := golloom.NewConversation(srv.Client(golloom.WithRetryPolicy(nil)), "llama3", "")
+	cv.SetHistoryPolicy(&golloom.HistoryPolicy{
+		Strategy:      golloom.HistorySummarize,
+		ContextLength: 40,
+		ReserveTokens: 1,
+	})
+
+	history := []golloom.Message{
+		{Role: "user", Content: strings.Repeat("a", 40)},
+		{Role: "assistant", Content: strings.Repeat("b", 40)},
+		{Role: "user", Content: "again"},
+		{Role: "assistant", Content: strings.Repeat("c", 40)},
+	}
+
+	cv.Append(history...)
+
+	srv.Enqueue(
+		"/api/chat",
+		golloomtest.ChatResponse("the summary"),
+		golloomtest.ErrorResponse(http.StatusInternalServerError, "boom"),
+	)
+
+	if _, err := cv.Send(context.Background(), "again"); err == nil {
+		t.Fatal("expected the scripted error")
+	}
+
+	if got := cv.Messages(); !reflect.DeepEqual(got, history) {
+		t.Errorf("history after a failed turn = %+v, want it unchanged", got)
+	}
+
+	if got := cv.Summary(); got != "" {
+		t.Errorf("summary after a failed turn = %q, want none", got)
+	}
+
+	srv.Enqueue("/api/chat", golloomtest.ChatResponse("the summary"))
+
+	if _, err := cv.Send(context.Background(), "again"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := cv.Summary(); got != "the summary" {
+		t.Errorf("summary = %q", got)
+	}
+
+	if got := len(cv.Messages()); got >= len(history)+2 {
+		t.Errorf("history holds %d messages; nothing was evicted", got)
+	}
+}
@@ -43,11 +43,29 @@ type CopyModelResult struct {
 func (c *Client) CopyModel(
 	ctx context.Context,
 	source, destination string,
+) (*CopyModelResult, error) {
+	return c.CopyModelProgress(ctx, source, destination, nil)
+}
+
+// CopyModelProgress copies a model like CopyModel, reporting every status line to fn as a ProgressEvent.
+// Parameters:
+//   - ctx: A context.Context object for managing request deadlines and cancellations.
+//   - source: The name or identifier of the source model to be copied.
+//   - destination: The target name or identifier where the model should be copied to.
+//   - fn: An optional callback receiving each event; a non-nil error aborts the operation.
+//
+// Returns:
+//   - A pointer to a CopyModelResult struct containing status messages from the operation.
+//   - An error if the request fails, the server reports an error, or fn aborts the operation.
+func (c *Client) CopyModelProgress(
+	ctx context.Context,
+	source, destination string,
+	fn ProgressFunc,
 ) (*CopyModelResult, error) {
 	rel := &url.URL{Path: "/api/copy"}
-	u := c.BaseURL.ResolveReference(rel)
+	u := c.resolve(rel)
 
-	res, err := c.sendStatusStreamRequest(
+	msgs, err := c.sendProgressStreamRequest(
 		ctx,
 		"POST",
 		u.String(),
@@ -55,6 +73,7 @@ func (c *Client) CopyModel(
 			"source":      source,
 			"destination": destination,
 		},
+		fn,
 	)
 
 	if err != nil {
@@ -62,6 +81,6 @@ func (c *Client) CopyModel(
 	}
 
 	return &CopyModelResult{
-		StatusMessages: res.StatusMessages,
+		StatusMessages: msgs,
 	}, nil
 }
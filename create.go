@@ -32,7 +32,7 @@ type CreateModelRequest struct {
 	Template   string                 `json:"template,omitempty"`   // Optional. A template defining the model's structure or behavior.
 	License    interface{}            `json:"license,omitempty"`    // Optional. License information for the model.
 	System     string                 `json:"system,omitempty"`     // Optional. System-specific parameters or configurations.
-	Parameters map[string]interface{} `json:"parameters,omitempty"` // Optional. Additional parameters for model creation.
+	Parameters map[string]interface{} `json:"parameters,omitempty"` // Optional. Additional parameters for model creation (see ModelOptions.ToMap).
 	Messages   []Message              `json:"messages,omitempty"`   // Optional. A sequence of messages or instructions related to the model.
 	Stream     *bool                  `json:"stream,omitempty"`     // Optional. Indicates if the creation process should be streamed.
 	Quantize   string                 `json:"quantize,omitempty"`   // Optional. Specifies quantization settings for the model.
@@ -57,15 +57,34 @@ type CreateModelResult struct {
 func (c *Client) CreateModel(
 	ctx context.Context,
 	req *CreateModelRequest,
+) (*CreateModelResult, error) {
+	return c.CreateModelProgress(ctx, req, nil)
+}
+
+// CreateModelProgress creates a new model like CreateModel, reporting every status line to fn
+// as a ProgressEvent so callers can follow layer transfers while the model is assembled.
+// Parameters:
+//   - ctx: A context.Context object for managing request deadlines and cancellations.
+//   - req: A pointer to a CreateModelRequest struct containing the model creation parameters.
+//   - fn: An optional callback receiving each event; a non-nil error aborts the operation.
+//
+// Returns:
+//   - A pointer to a CreateModelResult struct containing status messages from the operation.
+//   - An error if the request fails, the server reports an error, or fn aborts the operation.
+func (c *Client) CreateModelProgress(
+	ctx context.Context,
+	req *CreateModelRequest,
+	fn ProgressFunc,
 ) (*CreateModelResult, error) {
 	rel := &url.URL{Path: "/api/create"}
-	u := c.BaseURL.ResolveReference(rel)
+	u := c.resolve(rel)
 
-	res, err := c.sendStatusStreamRequest(
+	msgs, err := c.sendProgressStreamRequest(
 		ctx,
 		"POST",
 		u.String(),
 		req,
+		fn,
 	)
 
 	if err != nil {
@@ -73,6 +92,6 @@ func (c *Client) CreateModel(
 	}
 
 	return &CreateModelResult{
-		StatusMessages: res.StatusMessages,
+		StatusMessages: msgs,
 	}, nil
 }
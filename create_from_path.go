@@ -0,0 +1,179 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloom
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// CreateModelFromPath creates a model from local weights in one call. The weights and the optional
+// LoRA adapters are each given as a single GGUF or safetensors file, or as a directory holding them.
+// Every file is uploaded with UploadFileProgress, skipping blobs the server already has, and the
+// resulting digests fill in the Files and Adapters of a copy of req before CreateModelProgress is called.
+// A directory must hold either GGUF or safetensors weights, not both: its GGUF files are used, or
+// its safetensors files together with its JSON configuration files and tokenizer.model.
+// Parameters:
+//   - ctx: A context.Context object for managing request deadlines and cancellations.
+//   - req: The model creation parameters, such as Model, Template, and Parameters; it is left untouched.
+//   - weightsPath: The file or directory holding the model weights; empty when req.From names a base model.
+//   - adapterPath: The file or directory holding LoRA adapters; empty for none.
+//   - fn: An optional callback receiving the upload and creation events; a non-nil error aborts the operation.
+//
+// Returns:
+//   - A pointer to a CreateModelResult struct containing status messages from the creation.
+//   - An error if no usable files are found, an upload fails, or the creation fails.
+func (c *Client) CreateModelFromPath(
+	ctx context.Context,
+	req *CreateModelRequest,
+	weightsPath, adapterPath string,
+	fn ProgressFunc,
+) (*CreateModelResult, error) {
+	if req.Model == "" {
+		return nil, fmt.Errorf("model name must not be empty")
+	}
+
+	if weightsPath == "" && adapterPath == "" {
+		return nil, fmt.Errorf("a weights or adapter path is required")
+	}
+
+	createReq := *req
+	createReq.Files = maps.Clone(req.Files)
+	createReq.Adapters = maps.Clone(req.Adapters)
+
+	if weightsPath != "" {
+		files, err := c.uploadModelFiles(ctx, weightsPath, fn)
+		if err != nil {
+			return nil, err
+		}
+
+		if createReq.Files == nil {
+			createReq.Files = make(map[string]string)
+		}
+
+		maps.Copy(createReq.Files, files)
+	}
+
+	if adapterPath != "" {
+		adapters, err := c.uploadModelFiles(ctx, adapterPath, fn)
+		if err != nil {
+			return nil, err
+		}
+
+		if createReq.Adapters == nil {
+			createReq.Adapters = make(map[string]string)
+		}
+
+		maps.Copy(createReq.Adapters, adapters)
+	}
+
+	return c.CreateModelProgress(ctx, &createReq, fn)
+}
+
+// uploadModelFiles uploads the model files found at path and maps their names to their digests.
+func (c *Client) uploadModelFiles(
+	ctx context.Context,
+	path string,
+	fn ProgressFunc,
+) (map[string]string, error) {
+	files, err := modelFiles(path)
+	if err != nil {
+		return nil, err
+	}
+
+	digests := make(map[string]string, len(files))
+	for _, file := range files {
+		digest, err := c.UploadFileProgress(ctx, file, fn)
+		if err != nil {
+			return nil, fmt.Errorf("uploading %s: %w", file, err)
+		}
+
+		digests[filepath.Base(file)] = digest
+	}
+
+	return digests, nil
+}
+
+// modelFiles lists the files making up the weights at path: the file itself when it is a GGUF
+// or safetensors file, or the matching files of a directory, in lexical order. A directory holding
+// both GGUF and safetensors files is rejected rather than having one format silently ignored.
+func modelFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".gguf", ".safetensors":
+			return []string{path}, nil
+
+		default:
+			return nil, fmt.Errorf("%s is neither a GGUF nor a safetensors file", path)
+		}
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var gguf, safetensors, extra []string
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+
+		name := entry.Name()
+		full := filepath.Join(path, name)
+
+		switch ext := strings.ToLower(filepath.Ext(name)); {
+		case ext == ".gguf":
+			gguf = append(gguf, full)
+
+		case ext == ".safetensors":
+			safetensors = append(safetensors, full)
+
+		case ext == ".json" || name == "tokenizer.model":
+			extra = append(extra, full)
+		}
+	}
+
+	var files []string
+	switch {
+	case len(gguf) > 0 && len(safetensors) > 0:
+		return nil, fmt.Errorf("%s holds both GGUF and safetensors files", path)
+
+	case len(gguf) > 0:
+		files = gguf
+
+	case len(safetensors) > 0:
+		files = append(safetensors, extra...)
+
+	default:
+		return nil, fmt.Errorf("no GGUF or safetensors files found in %s", path)
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
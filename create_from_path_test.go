@@ -0,0 +1,110 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloom
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// modelDir creates a temporary directory holding empty files with the given names.
+func modelDir(t *testing.T, names ...string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func TestModelFiles(t *testing.T) {
+	single := modelDir(t, "model.GGUF", "notes.txt")
+
+	tests := []struct {
+		name string
+		path string
+		want []string
+	}{
+		{
+			name: "single file",
+			path: filepath.Join(single, "model.GGUF"),
+			want: []string{"model.GGUF"},
+		},
+		{
+			name: "gguf directory",
+			path: modelDir(t, "b.gguf", "a.gguf", "config.json", "README.md"),
+			want: []string{"a.gguf", "b.gguf"},
+		},
+		{
+			name: "safetensors directory",
+			path: modelDir(
+				t,
+				"model-00002.safetensors",
+				"model-00001.safetensors",
+				"config.json",
+				"tokenizer.json",
+				"tokenizer.model",
+				"README.md",
+			),
+			want: []string{
+				"config.json",
+				"model-00001.safetensors",
+				"model-00002.safetensors",
+				"tokenizer.json",
+				"tokenizer.model",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := modelFiles(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, file := range files {
+				got = append(got, filepath.Base(file))
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("files = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	failures := map[string]string{
+		"mixed directory": modelDir(t, "model.gguf", "model.safetensors", "config.json"),
+		"empty directory": modelDir(t),
+		"other file":      filepath.Join(single, "notes.txt"),
+		"missing path":    filepath.Join(single, "missing.gguf"),
+	}
+
+	for name, path := range failures {
+		if files, err := modelFiles(path); err == nil {
+			t.Errorf("%s: files = %v, want an error", name, files)
+		}
+	}
+}
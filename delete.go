@@ -50,7 +50,7 @@ func (c *Client) DeleteModel(
 	req *DeleteModelRequest,
 ) (*DeleteModelResult, error) {
 	rel := &url.URL{Path: "/api/delete"}
-	u := c.BaseURL.ResolveReference(rel)
+	u := c.resolve(rel)
 
 	res, err := c.sendStatusStreamRequest(
 		ctx,
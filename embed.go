@@ -26,9 +26,14 @@ import (
 // EmbedResult represents the response from an embedding operation.
 // It includes details about the model used, the creation timestamp, and the resulting embedding data.
 type EmbedResult struct {
-	Model     string      `json:"model"`      // The identifier of the model used to generate the embedding.
-	CreatedAt time.Time   `json:"created_at"` // The timestamp indicating when the embedding was created.
-	Embedding interface{} `json:"embedding"`  // The actual embedding data; its structure depends on the model's output.
+	Model      string      `json:"model"`                // The identifier of the model used to generate the embedding.
+	CreatedAt  time.Time   `json:"created_at"`           // The timestamp indicating when the embedding was created.
+	Embedding  interface{} `json:"embedding"`            // The actual embedding data; its structure depends on the model's output.
+	Embeddings [][]float32 `json:"embeddings,omitempty"` // The typed embedding vectors, one per input, in input order.
+
+	TotalDuration   int64 `json:"total_duration,omitempty"`    // Total time taken to generate the embeddings; optional field.
+	LoadDuration    int64 `json:"load_duration,omitempty"`     // Time taken to load the model; optional field.
+	PromptEvalCount int   `json:"prompt_eval_count,omitempty"` // Number of input tokens evaluated; optional field.
 }
 
 // Embed sends a request to generate an embedding for the given input using the specified model and options.
@@ -48,7 +53,7 @@ func (c *Client) Embed(
 	options map[string]interface{},
 ) (*EmbedResult, error) {
 	rel := &url.URL{Path: "/api/embed"}
-	u := c.BaseURL.ResolveReference(rel)
+	u := c.resolve(rel)
 
 	return c.sendEmbedRequest(
 		ctx,
@@ -0,0 +1,212 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloom
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+)
+
+// EmbedBatchOptions configures a batch embedding request made with EmbedBatch.
+// The zero value sends inputs in batches of 64 with up to 4 requests in flight.
+type EmbedBatchOptions struct {
+	Truncate  *bool                  // Whether inputs longer than the context are truncated instead of rejected; optional field.
+	KeepAlive string                 // How long the model stays loaded after the request; optional field.
+	Options   map[string]interface{} // Additional model options (see ModelOptions.ToMap); optional field.
+
+	BatchSize   int // The maximum number of inputs sent in a single request.
+	Concurrency int // The maximum number of requests in flight at the same time.
+}
+
+// embedRequest is the payload sent to the /api/embed endpoint for a batch of inputs.
+type embedRequest struct {
+	Model     string                 `json:"model"`
+	Input     []string               `json:"input"`
+	Truncate  *bool                  `json:"truncate,omitempty"`
+	Options   map[string]interface{} `json:"options,omitempty"`
+	KeepAlive string                 `json:"keep_alive,omitempty"`
+}
+
+// EmbedBatch generates embeddings for many inputs using the specified model.
+// Inputs are split into batches that are sent concurrently, with a bounded number of requests
+// in flight, and the resulting vectors are returned in the same order as the inputs. The first
+// failing batch cancels the remaining ones.
+// Parameters:
+//   - ctx: A context.Context object for managing request deadlines and cancellations.
+//   - model: The name or identifier of the model to use for generating the embeddings.
+//   - inputs: The texts to embed.
+//   - opts: Optional batching and request settings; nil uses the defaults.
+//
+// Returns:
+//   - A pointer to an EmbedResult whose Embeddings hold one vector per input and whose
+//     PromptEvalCount and durations are summed across all batches.
+//   - An error if any batch fails or the server returns an unexpected number of vectors.
+func (c *Client) EmbedBatch(
+	ctx context.Context,
+	model string,
+	inputs []string,
+	opts *EmbedBatchOptions,
+) (*EmbedResult, error) {
+	if opts == nil {
+		opts = &EmbedBatchOptions{}
+	}
+
+	rel := &url.URL{Path: "/api/embed"}
+	u := c.resolve(rel)
+
+	return embedInBatches(
+		ctx,
+		model,
+		inputs,
+		opts,
+		func(ctx context.Context, batch []string) (*EmbedResult, error) {
+			return c.sendEmbedRequest(
+				ctx,
+				"POST",
+				u.String(),
+				&embedRequest{
+					Model:     model,
+					Input:     batch,
+					Truncate:  opts.Truncate,
+					Options:   opts.Options,
+					KeepAlive: opts.KeepAlive,
+				},
+			)
+		},
+	)
+}
+
+// embedInBatches splits inputs into batches according to opts and passes them to send
+// concurrently, with a bounded number of calls in flight. The first failing batch cancels
+// the batches in flight, and no further batch is sent. The results are merged in input
+// order, with PromptEvalCount and durations summed across all batches.
+// Parameters:
+//   - ctx: A context.Context object for managing request deadlines and cancellations.
+//   - model: The model reported in the merged result when the server reports none.
+//   - inputs: The texts to embed.
+//   - opts: The batching settings; must not be nil.
+//   - send: Embeds a single batch, returning one vector per input.
+//
+// Returns:
+//   - A pointer to the merged EmbedResult.
+//   - An error if any batch fails or returns an unexpected number of vectors.
+func embedInBatches(
+	ctx context.Context,
+	model string,
+	inputs []string,
+	opts *EmbedBatchOptions,
+	send func(ctx context.Context, batch []string) (*EmbedResult, error),
+) (*EmbedResult, error) {
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = 64
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	numBatches := (len(inputs) + batchSize - 1) / batchSize
+	results := make([]*EmbedResult, numBatches)
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+
+	sem := make(chan struct{}, concurrency)
+
+dispatch:
+	for i := 0; i < numBatches; i++ {
+		start := i * batchSize
+		end := min(start+batchSize, len(inputs))
+
+		if ctx.Err() != nil {
+			break
+		}
+
+		select {
+		case sem <- struct{}{}:
+
+		case <-ctx.Done():
+			break dispatch
+		}
+
+		wg.Add(1)
+		go func(i int, batch []string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			res, err := send(ctx, batch)
+
+			if err == nil && len(res.Embeddings) != len(batch) {
+				err = fmt.Errorf(
+					"expected %d embeddings, got %d",
+					len(batch),
+					len(res.Embeddings),
+				)
+			}
+
+			if err != nil {
+				errOnce.Do(func() {
+					firstErr = fmt.Errorf("embedding batch %d: %w", i, err)
+					cancel()
+				})
+
+				return
+			}
+
+			results[i] = res
+		}(i, inputs[start:end])
+	}
+
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	out := &EmbedResult{
+		Model:      model,
+		Embeddings: make([][]float32, 0, len(inputs)),
+	}
+
+	for _, res := range results {
+		if res.Model != "" {
+			out.Model = res.Model
+		}
+
+		out.CreatedAt = res.CreatedAt
+		out.Embeddings = append(out.Embeddings, res.Embeddings...)
+		out.TotalDuration += res.TotalDuration
+		out.LoadDuration += res.LoadDuration
+		out.PromptEvalCount += res.PromptEvalCount
+	}
+
+	return out, nil
+}
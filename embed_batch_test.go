@@ -0,0 +1,104 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloom
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestEmbedInBatchesMergesInOrder(t *testing.T) {
+	inputs := []string{"a", "bb", "ccc", "dddd", "eeeee"}
+	opts := &EmbedBatchOptions{BatchSize: 2, Concurrency: 3}
+
+	res, err := embedInBatches(
+		context.Background(),
+		"m",
+		inputs,
+		opts,
+		func(_ context.Context, batch []string) (*EmbedResult, error) {
+			out := &EmbedResult{PromptEvalCount: len(batch)}
+			for _, in := range batch {
+				out.Embeddings = append(out.Embeddings, []float32{float32(len(in))})
+			}
+
+			return out, nil
+		},
+	)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Model != "m" || res.PromptEvalCount != len(inputs) {
+		t.Errorf("model = %q, prompt eval count = %d", res.Model, res.PromptEvalCount)
+	}
+
+	for i, vec := range res.Embeddings {
+		if int(vec[0]) != len(inputs[i]) {
+			t.Errorf("embedding %d = %v, want [%d]", i, vec, len(inputs[i]))
+		}
+	}
+}
+
+func TestEmbedInBatchesStopsAfterFailure(t *testing.T) {
+	var calls atomic.Int32
+	failure := errors.New("boom")
+
+	_, err := embedInBatches(
+		context.Background(),
+		"m",
+		strings.Split("abcdefghij", ""),
+		&EmbedBatchOptions{BatchSize: 1, Concurrency: 1},
+		func(context.Context, []string) (*EmbedResult, error) {
+			calls.Add(1)
+			return nil, failure
+		},
+	)
+
+	if !errors.Is(err, failure) {
+		t.Fatalf("err = %v, want the batch error", err)
+	}
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("send called %d times, want 1", got)
+	}
+}
+
+func TestEmbedInBatchesReportsCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := embedInBatches(
+		ctx,
+		"m",
+		[]string{"a", "b"},
+		&EmbedBatchOptions{BatchSize: 1},
+		func(context.Context, []string) (*EmbedResult, error) {
+			t.Error("send called after cancellation")
+			return nil, nil
+		},
+	)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+}
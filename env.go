@@ -0,0 +1,126 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloom
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ClientFromEnvironment creates a new Client whose base URL is taken from the OLLAMA_HOST
+// environment variable, normalized the same way the Ollama CLI does. Additional options are
+// applied after the base URL, so they may override it or configure the HTTP client further.
+// Parameters:
+//   - opts: Additional Option values applied after the environment has been read.
+//
+// Returns:
+//   - A pointer to a Client instance configured from the environment.
+//   - An error if the resulting base URL or any option cannot be applied.
+func ClientFromEnvironment(opts ...Option) (*Client, error) {
+	host := parseOllamaHost(os.Getenv("OLLAMA_HOST"))
+
+	return NewClientWithOptions(append(
+		[]Option{WithBaseURL(host.String())},
+		opts...,
+	)...)
+}
+
+// parseOllamaHost normalizes the value of OLLAMA_HOST into a base URL. It accepts a bare host,
+// a host and port such as "0.0.0.0:11434", a full "http://" or "https://" URL optionally
+// followed by a path, and "unix://" socket paths. Missing parts default to 127.0.0.1 and the
+// port implied by the scheme, or 11434 when no scheme is given.
+func parseOllamaHost(s string) *url.URL {
+	s = strings.Trim(strings.TrimSpace(s), "\"'")
+
+	defaultPort := "11434"
+	scheme, hostport, ok := strings.Cut(s, "://")
+
+	switch {
+	case !ok:
+		scheme, hostport = "http", s
+
+	case scheme == "unix":
+		return &url.URL{Scheme: scheme, Path: hostport}
+
+	case scheme == "http":
+		defaultPort = "80"
+
+	case scheme == "https":
+		defaultPort = "443"
+	}
+
+	hostport, path, _ := strings.Cut(hostport, "/")
+	host, port, err := net.SplitHostPort(hostport)
+
+	if err != nil {
+		host, port = "127.0.0.1", defaultPort
+		if ip := net.ParseIP(strings.Trim(hostport, "[]")); ip != nil {
+			host = ip.String()
+		} else if hostport != "" {
+			host = hostport
+		}
+	}
+
+	if n, err := strconv.ParseInt(port, 10, 32); err != nil || n < 0 || n > 65535 {
+		port = defaultPort
+	}
+
+	u := &url.URL{
+		Scheme: scheme,
+		Host:   net.JoinHostPort(host, port),
+	}
+
+	if path != "" {
+		u.Path = "/" + path
+	}
+
+	return u
+}
+
+// WithUnixSocket configures the client to reach the server through the Unix domain socket at
+// socketPath. The base URL is set to "http://localhost" and the HTTP client's transport dials
+// the socket for every connection.
+func WithUnixSocket(socketPath string) Option {
+	return func(c *Client) error {
+		if socketPath == "" {
+			return fmt.Errorf("unix socket path must not be empty")
+		}
+
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.DialContext = func(
+			ctx context.Context,
+			_, _ string,
+		) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", socketPath)
+		}
+
+		c.BaseURL = &url.URL{
+			Scheme: "http",
+			Host:   "localhost",
+		}
+
+		return WithTransport(transport)(c)
+	}
+}
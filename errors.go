@@ -0,0 +1,135 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloom
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// APIError represents an error reported by the Ollama server, either through a non-2xx HTTP
+// response or through an "error" line in the middle of a streamed response.
+type APIError struct {
+	StatusCode int    // The HTTP status code of the response that carried the error.
+	Message    string // The message from the server's "error" field, or the raw body if none was present.
+	Endpoint   string // The API path of the request that failed, e.g. "/api/chat".
+}
+
+// Error implements the error interface, formatting the endpoint, status code, and server message.
+func (e *APIError) Error() string {
+	return fmt.Sprintf(
+		"%s failed with status %d: %s",
+		e.Endpoint,
+		e.StatusCode,
+		e.Message,
+	)
+}
+
+// newAPIError builds an APIError from a failed HTTP response, reading a bounded amount of its body.
+// The server's JSON "error" field is preferred; otherwise the raw body or the status text is used.
+func newAPIError(resp *http.Response) *APIError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+
+	var errBody struct {
+		Error json.RawMessage `json:"error"`
+	}
+
+	message := strings.TrimSpace(string(body))
+	if err := json.Unmarshal(body, &errBody); err == nil {
+		if msg := errorMessage(errBody.Error); msg != "" {
+			message = msg
+		}
+	}
+
+	if message == "" {
+		message = http.StatusText(resp.StatusCode)
+	}
+
+	return &APIError{
+		StatusCode: resp.StatusCode,
+		Message:    message,
+		Endpoint:   responseEndpoint(resp),
+	}
+}
+
+// errorMessage extracts the message from an "error" field, which Ollama sends as a string
+// and OpenAI-compatible servers send as an object with a "message" field.
+func errorMessage(raw json.RawMessage) string {
+	var msg string
+	if err := json.Unmarshal(raw, &msg); err == nil {
+		return msg
+	}
+
+	var obj struct {
+		Message string `json:"message"`
+	}
+
+	if err := json.Unmarshal(raw, &obj); err == nil {
+		return obj.Message
+	}
+
+	return ""
+}
+
+// responseEndpoint returns the API path of the request that produced resp, if known.
+func responseEndpoint(resp *http.Response) string {
+	if resp.Request == nil || resp.Request.URL == nil {
+		return ""
+	}
+
+	return resp.Request.URL.Path
+}
+
+// checkResponse returns an *APIError when resp carries a non-2xx status code, and nil otherwise.
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return newAPIError(resp)
+	}
+
+	return nil
+}
+
+// IsModelNotFound reports whether err is an APIError indicating that the requested model
+// does not exist on the server, typically because it has not been pulled yet.
+func IsModelNotFound(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	msg := strings.ToLower(apiErr.Message)
+	return strings.Contains(msg, "model") &&
+		strings.Contains(msg, "not found")
+}
+
+// IsContextLengthExceeded reports whether err is an APIError indicating that the input
+// is longer than the context window of the model.
+func IsContextLengthExceeded(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	msg := strings.ToLower(apiErr.Message)
+	return strings.Contains(msg, "context length") ||
+		strings.Contains(msg, "context window")
+}
@@ -1,14 +1,9 @@
 package main
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"flag"
 	"fmt"
-	"io"
-	"net/http"
-	"net/url"
 	"os"
 	"time"
 
@@ -143,55 +138,17 @@ func doChat(ctx context.Context, client *golloom.Client, args []string) {
 		},
 	}
 
-	// Marshal the chat request into JSON format
-	reqBody, err := json.Marshal(chatReq)
-	if err != nil {
-		// Handle error in marshaling the request
-		fmt.Printf("Error marshaling chat request: %v\n", err)
-		os.Exit(1)
-	}
-
-	// Build the full URL for the chat API endpoint
-	reqURL := client.BaseURL.ResolveReference(&url.URL{Path: "/api/chat"}).String()
-
-	// Create a new HTTP POST request for the chat session
-	req, err := http.NewRequestWithContext(ctx, "POST", reqURL, bytes.NewReader(reqBody))
-	if err != nil {
-		// Handle error creating the HTTP request
-		fmt.Printf("Error creating request: %v\n", err)
-		os.Exit(1)
-	}
-	req.Header.Set("Content-Type", "application/json") // Set the correct content type for the request
+	// Stream the chat response, printing each chunk of content as it arrives
+	_, err := client.ChatStreamFunc(ctx, chatReq, func(chunk *golloom.ModelResponse) error {
+		fmt.Print(chunk.Message.Content)
+		return nil
+	})
 
-	// Execute the HTTP request using the client
-	resp, err := client.HTTPClient.Do(req)
 	if err != nil {
-		// Handle error during the HTTP request
-		fmt.Printf("Error during chat request: %v\n", err)
+		// Handle errors returned while streaming the chat response
+		fmt.Printf("\nError during chat request: %v\n", err)
 		os.Exit(1)
 	}
-	defer resp.Body.Close() // Ensure the response body is closed after reading
-
-	// Set up a JSON decoder to handle the streaming response
-	decoder := json.NewDecoder(resp.Body)
-	var fullContent string
-
-	// Loop through the streamed response chunks and print the content
-	for {
-		var chunk golloom.ModelResponse
-		err := decoder.Decode(&chunk)
-		if err != nil {
-			// Handle errors in decoding, particularly EOF (end of stream)
-			if err == io.EOF {
-				break // Exit loop if the stream ends
-			}
-			fmt.Printf("Error decoding chat stream: %v\n", err)
-			os.Exit(1)
-		}
-		// Print each chunk of content as it arrives
-		fmt.Print(chunk.Message.Content)
-		fullContent += chunk.Message.Content
-	}
 	fmt.Println() // Ensure a newline after printing the full response
 }
 
@@ -216,14 +173,12 @@ func doGenerate(ctx context.Context, client *golloom.Client, args []string) {
 		Prompt: *prompt,
 	}
 
-	// Call the client to generate a response based on the prompt
-	genResp, err := client.Generate(ctx, promptInfo)
-	if err != nil {
+	// Stream the generated tokens straight to standard output as they arrive
+	fmt.Println("Generated response:")
+	if _, err := client.GenerateStreamTo(ctx, promptInfo, os.Stdout); err != nil {
 		// Handle error in generating the response
-		fmt.Printf("Error during generation: %v\n", err)
+		fmt.Printf("\nError during generation: %v\n", err)
 		os.Exit(1)
 	}
-
-	// Print the generated response
-	fmt.Printf("Generated response:\n%s\n", genResp.Response)
+	fmt.Println() // Ensure a newline after printing the full response
 }
@@ -18,8 +18,9 @@ func main() {
 		log.Fatalf("Error creating client: %v", err) // Log and exit if client creation fails.
 	}
 
-	var history []golloom.Message // Slice to store the conversation history between the user and the assistant.
-	ctx := context.Background()   // Create a background context for the API requests.
+	// Create a conversation that keeps the history between the user and the assistant.
+	conversation := golloom.NewConversation(client, "deepseek-r1:14b", "")
+	ctx := context.Background() // Create a background context for the API requests.
 
 	reader := bufio.NewReader(os.Stdin) // Initialize a buffered reader to read user input from the standard input.
 
@@ -36,32 +37,12 @@ func main() {
 			break // Exit the loop if the user types "exit".
 		}
 
-		// Append the user's message to the conversation history.
-		history = append(history, golloom.Message{
-			Role:    "user",
-			Content: input,
-		})
-
-		// Create a chat request with the current conversation history.
-		chatReq := &golloom.Chat{
-			Model:    "deepseek-r1:14b", // Specify the model to be used for generating responses.
-			Messages: history,
-		}
-
-		// Send the chat request to the server and receive the response.
-		chatResp, err := client.Chat(ctx, chatReq)
+		// Send the user's message; the conversation records both it and the reply.
+		chatResp, err := conversation.Send(ctx, input)
 		if err != nil {
 			log.Fatalf("Chat error: %v", err) // Log and exit if the chat request fails.
 		}
 
-		// Extract the assistant's message from the response.
-		assistantMessage := chatResp.Message.Content
-		fmt.Println(assistantMessage) // Display the assistant's response.
-
-		// Append the assistant's message to the conversation history.
-		history = append(history, golloom.Message{
-			Role:    "assistant",
-			Content: assistantMessage,
-		})
+		fmt.Println(chatResp.Message.Content) // Display the assistant's response.
 	}
 }
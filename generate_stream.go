@@ -0,0 +1,162 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloom
+
+import (
+	"context"
+	"io"
+	"iter"
+	"net/url"
+)
+
+// GenerateStream sends a prompt generation request and streams every PromptResult chunk as it is produced.
+// The request is validated and always sent with streaming enabled; the caller's PromptInfo is left untouched.
+// Iteration stops after the final chunk, on the first error, or when the caller breaks out of the loop.
+// Parameters:
+//   - ctx: A context for controlling cancellation and timeouts for the request.
+//   - req: A pointer to a PromptInfo struct describing the generation request.
+//
+// Returns:
+//   - An iterator yielding each PromptResult chunk, or an error that ended the stream.
+func (c *Client) GenerateStream(
+	ctx context.Context,
+	req *PromptInfo,
+) iter.Seq2[*PromptResult, error] {
+	return func(yield func(*PromptResult, error) bool) {
+		if err := req.ValidatePromptInfo(); err != nil {
+			yield(nil, err)
+			return
+		}
+
+		stream := true
+		streamReq := *req
+		streamReq.Stream = &stream
+
+		rel := &url.URL{Path: "/api/generate"}
+		u := c.resolve(rel)
+
+		resp, err := c.sendStreamRequest(
+			ctx,
+			"POST",
+			u.String(),
+			&streamReq,
+		)
+
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer resp.Body.Close()
+
+		for chunk, err := range decodeStream[PromptResult](ctx, resp) {
+			if !yield(chunk, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// GenerateStreamFunc streams a generation like GenerateStream, invoking fn for every chunk as it arrives.
+// The chunks are stitched into a single PromptResult holding the full response text along with the
+// Context and metrics of the terminal chunk. If the context is cancelled or fn returns an error, the
+// partial result gathered so far is returned together with that error.
+// Parameters:
+//   - ctx: A context for controlling cancellation and timeouts for the request.
+//   - req: A pointer to a PromptInfo struct describing the generation request.
+//   - fn: An optional callback receiving each chunk; a non-nil error aborts the stream.
+//
+// Returns:
+//   - A pointer to the accumulated PromptResult, which is never nil.
+//   - An error if the request, the stream, or the callback fails.
+func (c *Client) GenerateStreamFunc(
+	ctx context.Context,
+	req *PromptInfo,
+	fn func(*PromptResult) error,
+) (*PromptResult, error) {
+	acc := &PromptResult{}
+	for chunk, err := range c.GenerateStream(ctx, req) {
+		if err != nil {
+			return acc, err
+		}
+
+		acc.merge(chunk)
+		if fn != nil {
+			if err := fn(chunk); err != nil {
+				return acc, err
+			}
+		}
+	}
+
+	return acc, nil
+}
+
+// GenerateStreamTo streams a generation and writes each token to w as soon as it is received.
+// It is a convenience wrapper around GenerateStreamFunc for command-line tools that print tokens live.
+// Parameters:
+//   - ctx: A context for controlling cancellation and timeouts for the request.
+//   - req: A pointer to a PromptInfo struct describing the generation request.
+//   - w: The io.Writer receiving the generated text.
+//
+// Returns:
+//   - A pointer to the accumulated PromptResult, which is never nil.
+//   - An error if the request, the stream, or a write fails.
+func (c *Client) GenerateStreamTo(
+	ctx context.Context,
+	req *PromptInfo,
+	w io.Writer,
+) (*PromptResult, error) {
+	return c.GenerateStreamFunc(
+		ctx,
+		req,
+		func(chunk *PromptResult) error {
+			_, err := io.WriteString(w, chunk.Response)
+			return err
+		},
+	)
+}
+
+// merge folds a streamed chunk into the accumulated result. Response text is appended, while
+// the Context and metrics are taken from the terminal chunk that carries them.
+func (r *PromptResult) merge(chunk *PromptResult) {
+	if chunk.Model != "" {
+		r.Model = chunk.Model
+	}
+
+	if !chunk.CreatedAt.IsZero() {
+		r.CreatedAt = chunk.CreatedAt
+	}
+
+	r.Response += chunk.Response
+	if chunk.Context != nil {
+		r.Context = chunk.Context
+	}
+
+	r.Done = chunk.Done
+	if chunk.DoneReason != "" {
+		r.DoneReason = chunk.DoneReason
+	}
+
+	if chunk.Done {
+		r.TotalDuration = chunk.TotalDuration
+		r.LoadDuration = chunk.LoadDuration
+		r.PromptEvalCount = chunk.PromptEvalCount
+		r.PromptEvalDuration = chunk.PromptEvalDuration
+		r.EvalCount = chunk.EvalCount
+		r.EvalDuration = chunk.EvalDuration
+	}
+}
@@ -0,0 +1,537 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloomtest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ErrNoInteraction is returned by a strict Recorder in replay mode when a request
+// matches no recorded interaction.
+var ErrNoInteraction = errors.New("no recorded interaction matches the request")
+
+// RecorderMode selects whether a Recorder talks to a real server or replays a cassette.
+type RecorderMode int
+
+const (
+	// ModeReplay serves every request from the cassette without touching the network.
+	ModeReplay RecorderMode = iota
+	// ModeRecord forwards every request to the real server and records the exchange.
+	ModeRecord
+	// ModeReplayOrRecord replays the cassette if its file exists, and records a new one otherwise.
+	ModeReplayOrRecord
+)
+
+// Cassette is the on-disk form of a recorded session: the interactions in the order
+// their responses completed.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a single recorded request and the response the server gave to it.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest describes a recorded request. JSON bodies are stored normalized, with
+// sorted keys and no insignificant whitespace; other bodies are stored as their digest only.
+type RecordedRequest struct {
+	Method     string          `json:"method"`                // The HTTP method of the request.
+	Path       string          `json:"path"`                  // The URL path of the request, e.g. "/api/chat".
+	Header     http.Header     `json:"header,omitempty"`      // The request headers, after redaction.
+	Body       json.RawMessage `json:"body,omitempty"`        // The normalized JSON body; optional field.
+	BodyDigest string          `json:"body_digest,omitempty"` // The "sha256:" digest of a non-JSON body; optional field.
+}
+
+// RecordedResponse describes a recorded response. Newline-delimited JSON streams are kept
+// as one entry per chunk so they are replayed incrementally.
+type RecordedResponse struct {
+	StatusCode int               `json:"status_code"`      // The HTTP status code of the response.
+	Header     http.Header       `json:"header,omitempty"` // The response headers, after redaction.
+	Body       json.RawMessage   `json:"body,omitempty"`   // The body when it is a single JSON document; optional field.
+	Text       string            `json:"text,omitempty"`   // The body when it is not JSON; optional field.
+	Chunks     []json.RawMessage `json:"chunks,omitempty"` // The objects of an NDJSON stream, in order; optional field.
+}
+
+// Recorder is an http.RoundTripper that records request/response pairs to a cassette file
+// and replays them offline. It is meant to be installed on a Client's HTTP client:
+//
+//	rec, err := golloomtest.NewRecorder("testdata/chat.json", golloomtest.ModeReplayOrRecord)
+//	client, err := golloom.NewClientWithOptions(golloom.WithTransport(rec))
+//	defer rec.Close()
+//
+// During replay, requests are matched on method, path and normalized JSON body. Each
+// interaction is served once, in recorded order; when every match has been used, the
+// last one is served again. A Recorder is safe for concurrent use.
+type Recorder struct {
+	// Transport sends requests to the real server while recording. When nil,
+	// http.DefaultTransport is used.
+	Transport http.RoundTripper
+	// Strict makes a replaying Recorder fail unmatched requests with ErrNoInteraction.
+	// When false, unmatched requests are forwarded to Transport without being recorded.
+	Strict bool
+	// RedactHeaders lists headers whose values are replaced with "REDACTED" before an
+	// interaction is stored. NewRecorder sets it to Authorization and Cookie.
+	RedactHeaders []string
+	// Redact is an optional hook called on every interaction before it is stored,
+	// e.g. to scrub secrets from bodies. During replay it is also called on each incoming
+	// request, wrapped in an Interaction with an empty Response, before matching, so a
+	// request body redacted while recording still matches the live request.
+	Redact func(*Interaction)
+
+	path  string
+	mode  RecorderMode
+	mu    sync.Mutex
+	tape  Cassette
+	used  []bool
+	dirty bool
+}
+
+// NewRecorder creates a Recorder backed by the cassette file at path.
+// Parameters:
+//   - path: The location of the cassette file.
+//   - mode: Whether to replay the file, record a new one, or decide based on whether it exists.
+//
+// Returns:
+//   - A pointer to the Recorder; replaying recorders are strict.
+//   - An error if a cassette to replay cannot be read or decoded.
+func NewRecorder(
+	path string,
+	mode RecorderMode,
+) (*Recorder, error) {
+	if mode == ModeReplayOrRecord {
+		mode = ModeRecord
+		if _, err := os.Stat(path); err == nil {
+			mode = ModeReplay
+		}
+	}
+
+	r := &Recorder{
+		Strict:        true,
+		RedactHeaders: []string{"Authorization", "Cookie"},
+		path:          path,
+		mode:          mode,
+	}
+
+	if mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(data, &r.tape); err != nil {
+			return nil, fmt.Errorf("decoding cassette %s: %w", path, err)
+		}
+
+		r.used = make([]bool, len(r.tape.Interactions))
+	}
+
+	return r, nil
+}
+
+// Mode returns the mode the Recorder operates in; ModeReplayOrRecord is resolved
+// to ModeReplay or ModeRecord by NewRecorder.
+func (r *Recorder) Mode() RecorderMode {
+	return r.mode
+}
+
+// Interactions returns a copy of the interactions in the cassette.
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Interaction(nil), r.tape.Interactions...)
+}
+
+// Unused returns the replayed interactions that were never matched by a request,
+// which usually means the code under test stopped making a call it used to make.
+func (r *Recorder) Unused() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var unused []Interaction
+	for i, used := range r.used {
+		if !used {
+			unused = append(unused, r.tape.Interactions[i])
+		}
+	}
+
+	return unused
+}
+
+// Close writes the cassette to disk if the Recorder recorded anything.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.mode != ModeRecord || !r.dirty {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(&r.tape, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(r.path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+
+	if err := os.WriteFile(r.path, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+
+	r.dirty = false
+	return nil
+}
+
+// RoundTrip implements http.RoundTripper, replaying or recording the exchange depending on the mode.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	recorded := RecordedRequest{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Header: r.redact(req.Header),
+	}
+
+	if normalized, ok := normalizeJSON(body); ok {
+		recorded.Body = normalized
+	} else if len(body) > 0 {
+		sum := sha256.Sum256(body)
+		recorded.BodyDigest = "sha256:" + hex.EncodeToString(sum[:])
+	}
+
+	if r.mode == ModeReplay {
+		probe := Interaction{Request: recorded}
+		if r.Redact != nil {
+			r.Redact(&probe)
+		}
+
+		if interaction, ok := r.match(&probe.Request); ok {
+			return interaction.Response.toHTTP(req), nil
+		}
+
+		if r.Strict {
+			return nil, fmt.Errorf(
+				"%w: %s %s",
+				ErrNoInteraction,
+				req.Method,
+				req.URL.Path,
+			)
+		}
+
+		return r.transport().RoundTrip(withBody(req, body))
+	}
+
+	resp, err := r.transport().RoundTrip(withBody(req, body))
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = &recordingBody{
+		ReadCloser: resp.Body,
+		done: func(data []byte) {
+			r.store(recorded, resp, data)
+		},
+	}
+
+	return resp, nil
+}
+
+// transport returns the RoundTripper used to reach the real server.
+func (r *Recorder) transport() http.RoundTripper {
+	if r.Transport != nil {
+		return r.Transport
+	}
+
+	return http.DefaultTransport
+}
+
+// match finds the interaction that answers req: the first unused match in recorded
+// order, or else the last match when all of them have been used.
+func (r *Recorder) match(req *RecordedRequest) (*Interaction, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reqBody, _ := normalizeJSON(req.Body)
+
+	last := -1
+	for i := range r.tape.Interactions {
+		rec := &r.tape.Interactions[i].Request
+		recBody, _ := normalizeJSON(rec.Body)
+
+		if rec.Method != req.Method ||
+			rec.Path != req.Path ||
+			!bytes.Equal(recBody, reqBody) ||
+			rec.BodyDigest != req.BodyDigest {
+			continue
+		}
+
+		if !r.used[i] {
+			r.used[i] = true
+			return &r.tape.Interactions[i], true
+		}
+
+		last = i
+	}
+
+	if last < 0 {
+		return nil, false
+	}
+
+	return &r.tape.Interactions[last], true
+}
+
+// store appends a completed exchange to the cassette.
+func (r *Recorder) store(
+	req RecordedRequest,
+	resp *http.Response,
+	body []byte,
+) {
+	interaction := Interaction{
+		Request: req,
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     r.redact(resp.Header),
+		},
+	}
+
+	if strings.Contains(resp.Header.Get("Content-Type"), "ndjson") {
+		for _, line := range bytes.Split(body, []byte("\n")) {
+			if len(bytes.TrimSpace(line)) > 0 {
+				interaction.Response.Chunks = append(
+					interaction.Response.Chunks,
+					json.RawMessage(line),
+				)
+			}
+		}
+	} else if json.Valid(body) {
+		interaction.Response.Body = json.RawMessage(bytes.TrimSpace(body))
+	} else {
+		interaction.Response.Text = string(body)
+	}
+
+	if r.Redact != nil {
+		r.Redact(&interaction)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tape.Interactions = append(r.tape.Interactions, interaction)
+	r.dirty = true
+}
+
+// redact returns a copy of h with the values of every header in RedactHeaders replaced.
+func (r *Recorder) redact(h http.Header) http.Header {
+	if len(h) == 0 {
+		return nil
+	}
+
+	out := h.Clone()
+	for _, key := range r.RedactHeaders {
+		if out.Get(key) != "" {
+			out.Set(key, "REDACTED")
+		}
+	}
+
+	return out
+}
+
+// toHTTP builds the http.Response replayed for req.
+func (rr *RecordedResponse) toHTTP(req *http.Request) *http.Response {
+	var chunks [][]byte
+	switch {
+	case rr.Chunks != nil:
+		for _, chunk := range rr.Chunks {
+			chunks = append(chunks, append(compactJSON(chunk), '\n'))
+		}
+
+	case rr.Body != nil:
+		chunks = [][]byte{compactJSON(rr.Body)}
+
+	case rr.Text != "":
+		chunks = [][]byte{[]byte(rr.Text)}
+	}
+
+	header := rr.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rr.StatusCode, http.StatusText(rr.StatusCode)),
+		StatusCode:    rr.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          &chunkReader{chunks: chunks},
+		ContentLength: -1,
+		Request:       req,
+	}
+}
+
+// recordingBody captures a response body as the caller reads it and hands the captured
+// bytes to done once the body is exhausted or closed.
+type recordingBody struct {
+	io.ReadCloser
+	buf  bytes.Buffer
+	once sync.Once
+	done func([]byte)
+}
+
+// Read reads from the underlying body, keeping a copy of everything read.
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.buf.Write(p[:n])
+
+	if err == io.EOF {
+		b.finish()
+	}
+
+	return n, err
+}
+
+// Close closes the underlying body and records what was read so far.
+func (b *recordingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.finish()
+
+	return err
+}
+
+// finish reports the captured body exactly once.
+func (b *recordingBody) finish() {
+	b.once.Do(func() {
+		b.done(b.buf.Bytes())
+	})
+}
+
+// chunkReader replays a response body one recorded chunk per Read call,
+// so streamed responses arrive incrementally as they did when recorded.
+type chunkReader struct {
+	chunks [][]byte
+}
+
+// Read copies as much of the current chunk as fits into p.
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.chunks) > 0 && len(r.chunks[0]) == 0 {
+		r.chunks = r.chunks[1:]
+	}
+
+	if len(r.chunks) == 0 {
+		return 0, io.EOF
+	}
+
+	n := copy(p, r.chunks[0])
+	r.chunks[0] = r.chunks[0][n:]
+
+	return n, nil
+}
+
+// Close implements io.Closer.
+func (r *chunkReader) Close() error {
+	return nil
+}
+
+// readRequestBody reads and closes the body of req, leaving req itself untouched.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	defer req.Body.Close()
+
+	return io.ReadAll(req.Body)
+}
+
+// withBody returns a shallow copy of req whose body replays data.
+func withBody(req *http.Request, data []byte) *http.Request {
+	out := req.Clone(req.Context())
+	if data == nil {
+		out.Body = http.NoBody
+		return out
+	}
+
+	out.Body = io.NopCloser(bytes.NewReader(data))
+	out.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+
+	return out
+}
+
+// normalizeJSON re-encodes a JSON document with sorted keys and no insignificant whitespace,
+// so semantically equal bodies compare equal. The second result is false for non-JSON data.
+func normalizeJSON(data []byte) (json.RawMessage, bool) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, false
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, false
+	}
+
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, false
+	}
+
+	normalized, err := json.Marshal(v)
+	if err != nil {
+		return nil, false
+	}
+
+	return normalized, true
+}
+
+// compactJSON strips insignificant whitespace from a stored JSON document, which
+// may have been indented when the cassette was written.
+func compactJSON(data json.RawMessage) []byte {
+	if len(data) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return data
+	}
+
+	return buf.Bytes()
+}
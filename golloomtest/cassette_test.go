@@ -0,0 +1,314 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloomtest_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nthnn/golloom"
+	"github.com/nthnn/golloom/golloomtest"
+)
+
+// recorderClient returns a client sending requests for baseURL through rec.
+func recorderClient(
+	t *testing.T,
+	baseURL string,
+	rec *golloomtest.Recorder,
+	opts ...golloom.Option,
+) *golloom.Client {
+	t.Helper()
+
+	client, err := golloom.NewClientWithOptions(append(
+		[]golloom.Option{
+			golloom.WithBaseURL(baseURL),
+			golloom.WithTransport(rec),
+		},
+		opts...,
+	)...)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return client
+}
+
+// streamText concatenates the content of every chunk of a chat stream.
+func streamText(t *testing.T, client *golloom.Client, req *golloom.Chat) (string, int) {
+	t.Helper()
+
+	var text strings.Builder
+	chunks := 0
+
+	for chunk, err := range client.ChatStream(context.Background(), req) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		text.WriteString(chunk.Message.Content)
+		chunks++
+	}
+
+	return text.String(), chunks
+}
+
+func TestRecorderRecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassettes", "chat.json")
+	req := &golloom.Chat{
+		Model:    "llama3",
+		Messages: []golloom.Message{{Role: "user", Content: "recorded reply"}},
+	}
+
+	srv := golloomtest.NewServer()
+	srv.SetVersion("9.9.9")
+
+	rec, err := golloomtest.NewRecorder(path, golloomtest.ModeReplayOrRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.Mode() != golloomtest.ModeRecord {
+		t.Fatalf("mode = %v, want ModeRecord for a missing cassette", rec.Mode())
+	}
+
+	client := recorderClient(t, srv.URL, rec, golloom.WithBearerToken("secret-token"))
+	if _, err := client.Version(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	text, chunks := streamText(t, client, req)
+	if text != "recorded reply" || chunks != 2 {
+		t.Fatalf("recorded stream = %q in %d chunks", text, chunks)
+	}
+
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	srv.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(data), "secret-token") || !strings.Contains(string(data), "REDACTED") {
+		t.Error("the Authorization header was not redacted in the cassette")
+	}
+
+	replay, err := golloomtest.NewRecorder(path, golloomtest.ModeReplayOrRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if replay.Mode() != golloomtest.ModeReplay {
+		t.Fatalf("mode = %v, want ModeReplay for an existing cassette", replay.Mode())
+	}
+
+	if got := len(replay.Interactions()); got != 2 {
+		t.Fatalf("cassette holds %d interactions, want 2", got)
+	}
+
+	offline := recorderClient(t, srv.URL, replay)
+
+	version, err := offline.Version(context.Background())
+	if err != nil || version.Version != "9.9.9" {
+		t.Fatalf("replayed version = %+v, %v", version, err)
+	}
+
+	text, chunks = streamText(t, offline, req)
+	if text != "recorded reply" || chunks != 2 {
+		t.Errorf("replayed stream = %q in %d chunks", text, chunks)
+	}
+
+	if unused := replay.Unused(); len(unused) != 0 {
+		t.Errorf("%d interactions left unused", len(unused))
+	}
+}
+
+func TestRecorderStrictReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.json")
+	if err := os.WriteFile(path, []byte(`{"interactions":[]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	rec, err := golloomtest.NewRecorder(path, golloomtest.ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := recorderClient(t, "http://127.0.0.1:1", rec, golloom.WithRetryPolicy(nil))
+	if _, err := client.Version(context.Background()); !errors.Is(err, golloomtest.ErrNoInteraction) {
+		t.Errorf("err = %v, want ErrNoInteraction", err)
+	}
+
+	srv := golloomtest.NewServer()
+	defer srv.Close()
+
+	srv.SetVersion("live")
+
+	rec.Strict = false
+	rec.Transport = http.DefaultTransport
+
+	version, err := recorderClient(t, srv.URL, rec).Version(context.Background())
+	if err != nil || version.Version != "live" {
+		t.Errorf("forwarded version = %+v, %v", version, err)
+	}
+}
+
+func TestRecorderMatching(t *testing.T) {
+	srv := golloomtest.NewServer()
+	defer srv.Close()
+
+	srv.Enqueue(
+		"/api/echo",
+		golloomtest.Response{Body: map[string]string{"reply": "first"}},
+		golloomtest.Response{Body: map[string]string{"reply": "second"}},
+	)
+
+	path := filepath.Join(t.TempDir(), "echo.json")
+
+	rec, err := golloomtest.NewRecorder(path, golloomtest.ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec.Redact = func(in *golloomtest.Interaction) {
+		in.Response.Body = []byte(strings.ReplaceAll(string(in.Response.Body), "second", "scrubbed"))
+	}
+
+	post := func(client *http.Client, body string) string {
+		t.Helper()
+
+		resp, err := client.Post(srv.URL+"/api/echo", "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return strings.TrimSpace(string(data))
+	}
+
+	recording := &http.Client{Transport: rec}
+	post(recording, `{"a":1,"b":2}`)
+	post(recording, `{"a":1,"b":2}`)
+
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	replay, err := golloomtest.NewRecorder(path, golloomtest.ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replaying := &http.Client{Transport: replay}
+
+	want := []string{
+		`{"reply":"first"}`,
+		`{"reply":"scrubbed"}`,
+		`{"reply":"scrubbed"}`,
+	}
+
+	for i, w := range want {
+		if got := post(replaying, "{ \"b\": 2, \"a\": 1 }"); got != w {
+			t.Errorf("replay %d = %s, want %s", i, got, w)
+		}
+	}
+
+	if _, err := replaying.Post(srv.URL+"/api/echo", "application/json", strings.NewReader(`{"a":2}`)); !errors.Is(err, golloomtest.ErrNoInteraction) {
+		t.Errorf("err = %v, want ErrNoInteraction for a different body", err)
+	}
+}
+
+func TestRecorderRedactsRequestBodies(t *testing.T) {
+	srv := golloomtest.NewServer()
+	defer srv.Close()
+
+	srv.Enqueue("/api/echo", golloomtest.Response{Body: map[string]string{"token": "sk-secret"}})
+
+	scrub := func(in *golloomtest.Interaction) {
+		in.Request.Body = []byte(strings.ReplaceAll(string(in.Request.Body), "sk-secret", "REDACTED"))
+		in.Response.Body = []byte(strings.ReplaceAll(string(in.Response.Body), "sk-secret", "REDACTED"))
+	}
+
+	path := filepath.Join(t.TempDir(), "secret.json")
+
+	rec, err := golloomtest.NewRecorder(path, golloomtest.ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec.Redact = scrub
+	body := `{"api_key":"sk-secret","prompt":"hi"}`
+
+	resp, err := (&http.Client{Transport: rec}).Post(srv.URL+"/api/echo", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(data), "sk-secret") {
+		t.Fatalf("the cassette still holds the secret:\n%s", data)
+	}
+
+	replay, err := golloomtest.NewRecorder(path, golloomtest.ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replay.Redact = scrub
+
+	resp, err = (&http.Client{Transport: replay}).Post(srv.URL+"/api/echo", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("replaying a request whose body was redacted: %v", err)
+	}
+	defer resp.Body.Close()
+
+	replayed, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.TrimSpace(string(replayed)); got != `{"token":"REDACTED"}` {
+		t.Errorf("replayed body = %s", got)
+	}
+}
@@ -0,0 +1,387 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloomtest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/nthnn/golloom"
+)
+
+// SetVersion sets the version string reported by /api/version.
+func (s *Server) SetVersion(version string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.version = version
+}
+
+// AddModel adds models to the store listed by /api/tags and described by /api/show.
+// A model without a ModifiedAt time is stamped with the current time.
+func (s *Server) AddModel(models ...golloom.ModelInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, m := range models {
+		if m.ModifiedAt.IsZero() {
+			m.ModifiedAt = time.Now().UTC()
+		}
+
+		s.models = append(s.models, m)
+	}
+}
+
+// SetRunning sets the models reported as loaded by /api/ps.
+func (s *Server) SetRunning(models ...golloom.RunningModel) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.running = append([]golloom.RunningModel(nil), models...)
+}
+
+// Blob returns the content of a blob uploaded to /api/blobs, if any.
+func (s *Server) Blob(digest string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, ok := s.blobs[digest]
+	return data, ok
+}
+
+// findModel looks up a stored model by name, comparing names by their canonical
+// golloom.ModelRef form. The caller must hold s.mu.
+func (s *Server) findModel(name string) (golloom.ModelInfo, bool) {
+	ref, err := golloom.ParseModelRef(name)
+	if err != nil {
+		return golloom.ModelInfo{}, false
+	}
+
+	for _, m := range s.models {
+		if ref.Matches(&m) {
+			return m, true
+		}
+	}
+
+	return golloom.ModelInfo{}, false
+}
+
+// handleChat replies to a chat by echoing the content of the last message. Unless the
+// request disables streaming, the reply is streamed with one chunk per word.
+func (s *Server) handleChat(req *Request) Response {
+	var chat golloom.Chat
+	if err := decodeBody(req, &chat); err != nil {
+		return ErrorResponse(http.StatusBadRequest, err.Error())
+	}
+
+	var content string
+	if n := len(chat.Messages); n > 0 {
+		content = chat.Messages[n-1].Content
+	}
+
+	if !streaming(chat.Stream) {
+		resp := ChatResponse(content)
+		resp.Body.(*golloom.ModelResponse).Model = chat.Model
+
+		return resp
+	}
+
+	resp := ChatStreamResponse(words(content)...)
+	for _, chunk := range resp.Stream {
+		chunk.(*golloom.ModelResponse).Model = chat.Model
+	}
+
+	return resp
+}
+
+// handleGenerate replies to a generation request by echoing its prompt. Unless the
+// request disables streaming, the reply is streamed with one chunk per word.
+func (s *Server) handleGenerate(req *Request) Response {
+	var prompt golloom.PromptInfo
+	if err := decodeBody(req, &prompt); err != nil {
+		return ErrorResponse(http.StatusBadRequest, err.Error())
+	}
+
+	if !streaming(prompt.Stream) {
+		resp := GenerateResponse(prompt.Prompt)
+		resp.Body.(*golloom.PromptResult).Model = prompt.Model
+
+		return resp
+	}
+
+	resp := GenerateStreamResponse(words(prompt.Prompt)...)
+	for _, chunk := range resp.Stream {
+		chunk.(*golloom.PromptResult).Model = prompt.Model
+	}
+
+	return resp
+}
+
+// handleEmbed replies with one deterministic four-dimensional vector per input,
+// derived from a hash of the input text.
+func (s *Server) handleEmbed(req *Request) Response {
+	var body struct {
+		Model string      `json:"model"`
+		Input interface{} `json:"input"`
+	}
+
+	if err := decodeBody(req, &body); err != nil {
+		return ErrorResponse(http.StatusBadRequest, err.Error())
+	}
+
+	var inputs []string
+	switch input := body.Input.(type) {
+	case string:
+		inputs = []string{input}
+
+	case []interface{}:
+		for _, v := range input {
+			text, ok := v.(string)
+			if !ok {
+				return ErrorResponse(http.StatusBadRequest, "invalid input type")
+			}
+
+			inputs = append(inputs, text)
+		}
+	}
+
+	vectors := make([][]float32, 0, len(inputs))
+	for _, text := range inputs {
+		vectors = append(vectors, fakeVector(text))
+	}
+
+	resp := EmbedResponse(vectors...)
+	result := resp.Body.(*golloom.EmbedResult)
+	result.Model = body.Model
+	result.PromptEvalCount = len(inputs)
+
+	return resp
+}
+
+// handleTags lists the models in the store.
+func (s *Server) handleTags(req *Request) Response {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return Response{
+		Body: &golloom.ModelList{
+			Models: append([]golloom.ModelInfo{}, s.models...),
+		},
+	}
+}
+
+// handleShow describes a stored model, answering 404 for unknown models.
+func (s *Server) handleShow(req *Request) Response {
+	var body struct {
+		Model string `json:"model"`
+		Name  string `json:"name"`
+	}
+
+	if err := decodeBody(req, &body); err != nil {
+		return ErrorResponse(http.StatusBadRequest, err.Error())
+	}
+
+	name := body.Model
+	if name == "" {
+		name = body.Name
+	}
+
+	s.mu.Lock()
+	m, ok := s.findModel(name)
+	s.mu.Unlock()
+
+	if !ok {
+		return ErrorResponse(
+			http.StatusNotFound,
+			fmt.Sprintf("model '%s' not found", name),
+		)
+	}
+
+	family := m.Details.Family
+	if family == "" {
+		family = "llama"
+	}
+
+	return Response{
+		Body: map[string]interface{}{
+			"modelfile":  "FROM " + m.Name + "\n",
+			"parameters": "",
+			"template":   "{{ .Prompt }}",
+			"details":    m.Details,
+			"model_info": map[string]interface{}{
+				"general.architecture":       family,
+				family + ".context_length":   4096,
+				family + ".embedding_length": 4096,
+			},
+			"capabilities": []string{"completion"},
+			"modified_at":  m.ModifiedAt,
+		},
+	}
+}
+
+// handlePS lists the models set with SetRunning.
+func (s *Server) handlePS(req *Request) Response {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return Response{
+		Body: &golloom.ModelProcessStatus{
+			Models: append([]golloom.RunningModel{}, s.running...),
+		},
+	}
+}
+
+// handlePull streams the progress of a simulated download and adds the model to the store.
+func (s *Server) handlePull(req *Request) Response {
+	var body struct {
+		Model string `json:"model"`
+		Name  string `json:"name"`
+	}
+
+	if err := decodeBody(req, &body); err != nil {
+		return ErrorResponse(http.StatusBadRequest, err.Error())
+	}
+
+	name := body.Model
+	if name == "" {
+		name = body.Name
+	}
+
+	if name == "" {
+		return ErrorResponse(http.StatusBadRequest, "model is required")
+	}
+
+	digest := fakeDigest(name)
+
+	s.mu.Lock()
+	if _, ok := s.findModel(name); !ok {
+		s.models = append(s.models, golloom.ModelInfo{
+			Name:       shortName(name),
+			ModifiedAt: time.Now().UTC(),
+			Size:       1024,
+			Digest:     strings.TrimPrefix(digest, "sha256:"),
+			Details: golloom.ModelDetails{
+				Format:            "gguf",
+				Family:            "llama",
+				ParameterSize:     "1B",
+				QuantizationLevel: "Q4_0",
+			},
+		})
+	}
+	s.mu.Unlock()
+
+	return Response{
+		Stream: []interface{}{
+			&golloom.ProgressEvent{Status: "pulling manifest"},
+			&golloom.ProgressEvent{Status: "pulling " + digest[7:19], Digest: digest, Total: 1024, Completed: 512},
+			&golloom.ProgressEvent{Status: "pulling " + digest[7:19], Digest: digest, Total: 1024, Completed: 1024},
+			&golloom.ProgressEvent{Status: "verifying sha256 digest"},
+			&golloom.ProgressEvent{Status: "writing manifest"},
+			&golloom.ProgressEvent{Status: "success"},
+		},
+	}
+}
+
+// handleVersion reports the version set with SetVersion.
+func (s *Server) handleVersion(req *Request) Response {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return Response{
+		Body: &golloom.Version{Version: s.version},
+	}
+}
+
+// handleBlobs answers HEAD requests for stored blobs and stores uploaded blobs after
+// checking that their content matches the digest in the path, as Ollama does.
+func (s *Server) handleBlobs(req *Request) Response {
+	digest := strings.TrimPrefix(req.Path, "/api/blobs/")
+
+	switch req.Method {
+	case http.MethodHead:
+		if _, ok := s.Blob(digest); ok {
+			return Response{}
+		}
+
+		return Response{StatusCode: http.StatusNotFound}
+
+	case http.MethodPost:
+		sum := sha256.Sum256(req.Body)
+		if digest != "sha256:"+hex.EncodeToString(sum[:]) {
+			return ErrorResponse(http.StatusBadRequest, "digest mismatch")
+		}
+
+		s.mu.Lock()
+		s.blobs[digest] = req.Body
+		s.mu.Unlock()
+
+		return Response{StatusCode: http.StatusCreated}
+
+	default:
+		return ErrorResponse(
+			http.StatusMethodNotAllowed,
+			"method not allowed",
+		)
+	}
+}
+
+// fakeVector derives a deterministic unit-free vector from text.
+func fakeVector(text string) []float32 {
+	h := fnv.New64a()
+	h.Write([]byte(text))
+	sum := h.Sum64()
+
+	vec := make([]float32, 4)
+	for i := range vec {
+		vec[i] = float32(sum>>(16*i)&0xffff) / 0xffff
+	}
+
+	return vec
+}
+
+// fakeDigest derives a deterministic "sha256:" digest from a model name.
+func fakeDigest(name string) string {
+	sum := sha256.Sum256([]byte(name))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// shortName returns the canonical short form of a model name, as ListModels reports it.
+func shortName(name string) string {
+	if ref, err := golloom.ParseModelRef(name); err == nil {
+		return ref.String()
+	}
+
+	return name
+}
+
+// streaming reports whether a request with the given "stream" field expects a streamed
+// reply; like Ollama, it does unless streaming is disabled explicitly.
+func streaming(stream *bool) bool {
+	return stream == nil || *stream
+}
+
+// words splits text after each space, so the parts concatenate back to text.
+// Empty text yields a single empty part.
+func words(text string) []string {
+	return strings.SplitAfter(text, " ")
+}
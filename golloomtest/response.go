@@ -0,0 +1,214 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloomtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/nthnn/golloom"
+)
+
+// Response is a scripted reply served by the Server. When Stream is set, each element is
+// written as one line of newline-delimited JSON and flushed immediately; otherwise Body is
+// written as a single JSON document. A Body of type []byte or string is written verbatim.
+type Response struct {
+	StatusCode int           // The HTTP status code; zero means 200.
+	Header     http.Header   // Additional response headers; optional field.
+	Body       interface{}   // The JSON document sent as the body; optional field.
+	Stream     []interface{} // The objects sent as an NDJSON stream; optional field.
+	Delay      time.Duration // How long to wait before answering, or until the request is cancelled.
+}
+
+// write sends the response to w, honouring the cancellation of r while delayed.
+func (resp Response) write(w http.ResponseWriter, r *http.Request) {
+	if resp.Delay > 0 {
+		timer := time.NewTimer(resp.Delay)
+		defer timer.Stop()
+
+		select {
+		case <-r.Context().Done():
+			return
+
+		case <-timer.C:
+		}
+	}
+
+	for key, values := range resp.Header {
+		w.Header()[key] = append([]string(nil), values...)
+	}
+
+	status := resp.StatusCode
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	if resp.Stream != nil {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(status)
+
+		flusher, _ := w.(http.Flusher)
+		enc := json.NewEncoder(w)
+
+		for _, line := range resp.Stream {
+			if err := enc.Encode(line); err != nil {
+				return
+			}
+
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+
+		return
+	}
+
+	switch body := resp.Body.(type) {
+	case nil:
+		w.WriteHeader(status)
+
+	case []byte:
+		w.WriteHeader(status)
+		w.Write(body)
+
+	case string:
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+
+	default:
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(body)
+	}
+}
+
+// ErrorResponse returns a response with the given status code whose body carries message
+// in the "error" field, the way Ollama reports failures.
+func ErrorResponse(status int, message string) Response {
+	return Response{
+		StatusCode: status,
+		Body:       ErrorLine(message),
+	}
+}
+
+// ErrorLine returns an "error" object that can be placed in Response.Stream
+// to make a stream fail midway.
+func ErrorLine(message string) map[string]string {
+	return map[string]string{"error": message}
+}
+
+// ChatResponse returns a non-streamed chat reply carrying content from the assistant.
+func ChatResponse(content string) Response {
+	return Response{
+		Body: &golloom.ModelResponse{
+			CreatedAt:  time.Now().UTC(),
+			Message:    golloom.Message{Role: "assistant", Content: content},
+			Done:       true,
+			DoneReason: "stop",
+		},
+	}
+}
+
+// ChatStreamResponse returns a streamed chat reply with one chunk per part,
+// the last of which is marked done.
+func ChatStreamResponse(parts ...string) Response {
+	stream := make([]interface{}, 0, len(parts))
+	for i, part := range parts {
+		chunk := &golloom.ModelResponse{
+			CreatedAt: time.Now().UTC(),
+			Message:   golloom.Message{Role: "assistant", Content: part},
+		}
+
+		if i == len(parts)-1 {
+			chunk.Done = true
+			chunk.DoneReason = "stop"
+		}
+
+		stream = append(stream, chunk)
+	}
+
+	return Response{Stream: stream}
+}
+
+// ToolCallResponse returns a non-streamed chat reply in which the assistant asks
+// to invoke the given tools.
+func ToolCallResponse(calls ...golloom.ToolCall) Response {
+	return Response{
+		Body: &golloom.ModelResponse{
+			CreatedAt:  time.Now().UTC(),
+			Message:    golloom.Message{Role: "assistant", ToolCalls: calls},
+			Done:       true,
+			DoneReason: "stop",
+		},
+	}
+}
+
+// GenerateResponse returns a non-streamed generation reply carrying text.
+func GenerateResponse(text string) Response {
+	return Response{
+		Body: &golloom.PromptResult{
+			CreatedAt:  time.Now().UTC(),
+			Response:   text,
+			Done:       true,
+			DoneReason: "stop",
+		},
+	}
+}
+
+// GenerateStreamResponse returns a streamed generation reply with one chunk per part,
+// the last of which is marked done.
+func GenerateStreamResponse(parts ...string) Response {
+	stream := make([]interface{}, 0, len(parts))
+	for i, part := range parts {
+		chunk := &golloom.PromptResult{
+			CreatedAt: time.Now().UTC(),
+			Response:  part,
+		}
+
+		if i == len(parts)-1 {
+			chunk.Done = true
+			chunk.DoneReason = "stop"
+		}
+
+		stream = append(stream, chunk)
+	}
+
+	return Response{Stream: stream}
+}
+
+// EmbedResponse returns an embedding reply carrying the given vectors, one per input.
+func EmbedResponse(vectors ...[]float32) Response {
+	return Response{
+		Body: &golloom.EmbedResult{
+			CreatedAt:  time.Now().UTC(),
+			Embeddings: vectors,
+		},
+	}
+}
+
+// ProgressResponse returns a streamed progress reply, as sent by pull, push and create,
+// with one status line per message.
+func ProgressResponse(statuses ...string) Response {
+	stream := make([]interface{}, 0, len(statuses))
+	for _, status := range statuses {
+		stream = append(stream, &golloom.ProgressEvent{Status: status})
+	}
+
+	return Response{Stream: stream}
+}
@@ -0,0 +1,266 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+// Package golloomtest provides an in-process fake Ollama server for unit tests.
+// It answers the endpoints used by golloom with scripted or built-in responses,
+// records every request it receives, and hands back a Client pointed at itself:
+//
+//	srv := golloomtest.NewServer()
+//	defer srv.Close()
+//
+//	srv.Enqueue("/api/chat", golloomtest.ChatStreamResponse("Hel", "lo"))
+//	resp, err := srv.Client().Chat(ctx, &golloom.Chat{Model: "llama3"})
+//
+// For tests that should exercise a real model once and run offline afterwards,
+// Recorder captures HTTP exchanges to a cassette file and replays them.
+package golloomtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/nthnn/golloom"
+)
+
+// Request is a request received by the Server, recorded for later assertions.
+type Request struct {
+	Method string      // The HTTP method of the request.
+	Path   string      // The URL path of the request, e.g. "/api/chat".
+	Header http.Header // The headers sent with the request.
+	Body   []byte      // The raw request body.
+}
+
+// Decode unmarshals the JSON body of the request into v, e.g. a *golloom.Chat.
+func (r *Request) Decode(v interface{}) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// HandlerFunc computes the response to a request dynamically.
+type HandlerFunc func(req *Request) Response
+
+// Server is a fake Ollama server built on httptest.Server. Every endpoint has a built-in
+// handler backed by a small in-memory model store; responses queued with Enqueue take
+// precedence and are served once each, in order. It is safe for concurrent use.
+type Server struct {
+	// URL is the base URL of the server, e.g. "http://127.0.0.1:54321".
+	URL string
+
+	srv *httptest.Server
+	mu  sync.Mutex
+
+	requests []Request
+	queues   map[string][]Response
+	handlers map[string]HandlerFunc
+
+	version string
+	models  []golloom.ModelInfo
+	running []golloom.RunningModel
+	blobs   map[string][]byte
+}
+
+// NewServer starts a fake Ollama server with the built-in handlers for /api/chat, /api/generate,
+// /api/embed, /api/tags, /api/show, /api/ps, /api/pull, /api/version and /api/blobs.
+// The caller must call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		queues:  make(map[string][]Response),
+		version: "0.0.0-golloomtest",
+		blobs:   make(map[string][]byte),
+	}
+
+	s.handlers = map[string]HandlerFunc{
+		"/api/chat":     s.handleChat,
+		"/api/generate": s.handleGenerate,
+		"/api/embed":    s.handleEmbed,
+		"/api/tags":     s.handleTags,
+		"/api/show":     s.handleShow,
+		"/api/ps":       s.handlePS,
+		"/api/pull":     s.handlePull,
+		"/api/version":  s.handleVersion,
+		"/api/blobs":    s.handleBlobs,
+	}
+
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.srv.URL
+
+	return s
+}
+
+// Close shuts the server down and blocks until all outstanding requests have completed.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Client returns a Client whose base URL points at the server. Additional options are
+// applied after the base URL; Client panics if any of them fails.
+func (s *Server) Client(opts ...golloom.Option) *golloom.Client {
+	c, err := golloom.NewClientWithOptions(
+		append([]golloom.Option{golloom.WithBaseURL(s.URL)}, opts...)...,
+	)
+
+	if err != nil {
+		panic(fmt.Sprintf("golloomtest: creating client: %v", err))
+	}
+
+	return c
+}
+
+// Enqueue scripts responses for an endpoint. Each response is served once, in order,
+// before the endpoint falls back to its handler. Paths under "/api/blobs/" are scripted
+// with the path "/api/blobs".
+func (s *Server) Enqueue(path string, responses ...Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.queues[path] = append(s.queues[path], responses...)
+}
+
+// Handle replaces the handler of an endpoint, or adds one for a path the server does not know.
+// Passing a nil handler makes the endpoint answer 404.
+func (s *Server) Handle(path string, handler HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if handler == nil {
+		delete(s.handlers, path)
+		return
+	}
+
+	s.handlers[path] = handler
+}
+
+// Requests returns a copy of every request received so far, in arrival order.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+// RequestsTo returns the requests received for the given endpoint, in arrival order.
+func (s *Server) RequestsTo(path string) []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var reqs []Request
+	for _, req := range s.requests {
+		if route(req.Path) == path {
+			reqs = append(reqs, req)
+		}
+	}
+
+	return reqs
+}
+
+// LastRequest returns the most recent request received for the given endpoint.
+// The second result is false when the endpoint has not been called.
+func (s *Server) LastRequest(path string) (Request, bool) {
+	reqs := s.RequestsTo(path)
+	if len(reqs) == 0 {
+		return Request{}, false
+	}
+
+	return reqs[len(reqs)-1], true
+}
+
+// Reset forgets the recorded requests and any responses still queued.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = nil
+	s.queues = make(map[string][]Response)
+}
+
+// serveHTTP records the request and answers it with the next queued response for its
+// endpoint, or else with the endpoint's handler.
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	req := Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Header: r.Header.Clone(),
+		Body:   body,
+	}
+
+	path := route(req.Path)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+
+	queued, hasQueued := s.dequeue(path)
+	handler := s.handlers[path]
+	s.mu.Unlock()
+
+	var resp Response
+	switch {
+	case hasQueued:
+		resp = queued
+
+	case handler != nil:
+		resp = handler(&req)
+
+	default:
+		resp = ErrorResponse(
+			http.StatusNotFound,
+			fmt.Sprintf("%s not found", req.Path),
+		)
+	}
+
+	resp.write(w, r)
+}
+
+// dequeue pops the next scripted response for path. The caller must hold s.mu.
+func (s *Server) dequeue(path string) (Response, bool) {
+	queue := s.queues[path]
+	if len(queue) == 0 {
+		return Response{}, false
+	}
+
+	s.queues[path] = queue[1:]
+	return queue[0], true
+}
+
+// route maps a request path to the endpoint key used for queues and handlers.
+func route(path string) string {
+	if strings.HasPrefix(path, "/api/blobs/") {
+		return "/api/blobs"
+	}
+
+	return path
+}
+
+// decodeBody is a helper for handlers decoding the JSON body of a request; an empty body
+// leaves v untouched.
+func decodeBody(req *Request, v interface{}) error {
+	if len(bytes.TrimSpace(req.Body)) == 0 {
+		return nil
+	}
+
+	return req.Decode(v)
+}
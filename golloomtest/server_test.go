@@ -0,0 +1,362 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloomtest_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nthnn/golloom"
+	"github.com/nthnn/golloom/golloomtest"
+)
+
+func TestServerChat(t *testing.T) {
+	srv := golloomtest.NewServer()
+	defer srv.Close()
+
+	stream := false
+	req := &golloom.Chat{
+		Model:    "llama3",
+		Messages: []golloom.Message{{Role: "user", Content: "hello there"}},
+		Stream:   &stream,
+	}
+
+	resp, err := srv.Client().Chat(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Model != "llama3" || resp.Message.Content != "hello there" || !resp.Done {
+		t.Errorf("chat response = %+v", resp)
+	}
+}
+
+func TestServerChatStream(t *testing.T) {
+	srv := golloomtest.NewServer()
+	defer srv.Close()
+
+	req := &golloom.Chat{
+		Model:    "llama3",
+		Messages: []golloom.Message{{Role: "user", Content: "one two three"}},
+	}
+
+	var parts []string
+	var last *golloom.ModelResponse
+
+	for chunk, err := range srv.Client().ChatStream(context.Background(), req) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if chunk.Model != "llama3" {
+			t.Errorf("chunk model = %q", chunk.Model)
+		}
+
+		parts = append(parts, chunk.Message.Content)
+		last = chunk
+	}
+
+	if want := []string{"one ", "two ", "three"}; !reflect.DeepEqual(parts, want) {
+		t.Errorf("chunks = %q, want %q", parts, want)
+	}
+
+	if last == nil || !last.Done {
+		t.Error("the last chunk is not marked done")
+	}
+}
+
+func TestServerGenerate(t *testing.T) {
+	srv := golloomtest.NewServer()
+	defer srv.Close()
+
+	client := srv.Client()
+	stream := false
+
+	resp, err := client.Generate(context.Background(), &golloom.PromptInfo{
+		Model:  "llama3",
+		Prompt: "why is the sky blue",
+		Stream: &stream,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Response != "why is the sky blue" || resp.Model != "llama3" {
+		t.Errorf("generate response = %+v", resp)
+	}
+
+	var chunks int
+	var text strings.Builder
+
+	for chunk, err := range client.GenerateStream(context.Background(), &golloom.PromptInfo{
+		Model:  "llama3",
+		Prompt: "a b c",
+	}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		chunks++
+		text.WriteString(chunk.Response)
+	}
+
+	if chunks != 3 || text.String() != "a b c" {
+		t.Errorf("streamed %d chunks %q, want 3 chunks of \"a b c\"", chunks, text.String())
+	}
+}
+
+func TestServerEmbed(t *testing.T) {
+	srv := golloomtest.NewServer()
+	defer srv.Close()
+
+	client := srv.Client()
+
+	res, err := client.EmbedBatch(
+		context.Background(),
+		"embedder",
+		[]string{"a", "b", "a"},
+		&golloom.EmbedBatchOptions{BatchSize: 2},
+	)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res.Embeddings) != 3 || len(res.Embeddings[0]) != 4 {
+		t.Fatalf("embeddings = %v, want three 4-dimensional vectors", res.Embeddings)
+	}
+
+	if !reflect.DeepEqual(res.Embeddings[0], res.Embeddings[2]) {
+		t.Error("equal inputs produced different vectors")
+	}
+
+	if reflect.DeepEqual(res.Embeddings[0], res.Embeddings[1]) {
+		t.Error("different inputs produced equal vectors")
+	}
+}
+
+func TestServerModels(t *testing.T) {
+	srv := golloomtest.NewServer()
+	defer srv.Close()
+
+	srv.AddModel(golloom.ModelInfo{
+		Name:    "llama3:latest",
+		Details: golloom.ModelDetails{Family: "llama"},
+	})
+
+	client := srv.Client()
+	ctx := context.Background()
+
+	list, err := client.ListModels(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(list.Models) != 1 || list.Models[0].ModifiedAt.IsZero() {
+		t.Fatalf("models = %+v, want llama3 with a modification time", list.Models)
+	}
+
+	info, err := client.FetchModelInfo(ctx, "llama3", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if length, ok := info.ContextLength(); !ok || length != 4096 {
+		t.Errorf("context length = %d, %v", length, ok)
+	}
+
+	if _, err := client.FetchModelInfo(ctx, "missing", false); !golloom.IsModelNotFound(err) {
+		t.Errorf("err = %v, want a model-not-found error", err)
+	}
+
+	var statuses []string
+	_, err = client.PullModelProgress(ctx, "mistral", func(ev *golloom.ProgressEvent) error {
+		statuses = append(statuses, ev.Status)
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(statuses) == 0 || statuses[len(statuses)-1] != "success" {
+		t.Errorf("pull statuses = %q, want them to end with success", statuses)
+	}
+
+	if _, err := client.FetchModelInfo(ctx, "mistral:latest", false); err != nil {
+		t.Errorf("pulled model is not known: %v", err)
+	}
+
+	srv.SetRunning(golloom.RunningModel{Name: "llama3:latest", Model: "llama3:latest"})
+
+	ps, err := client.ProcessStatus(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !ps.IsLoaded("llama3") {
+		t.Error("llama3 is not reported as loaded")
+	}
+
+	srv.SetVersion("1.2.3")
+
+	version, err := client.Version(ctx)
+	if err != nil || version.Version != "1.2.3" {
+		t.Errorf("version = %+v, %v", version, err)
+	}
+}
+
+func TestServerBlobs(t *testing.T) {
+	srv := golloomtest.NewServer()
+	defer srv.Close()
+
+	client := srv.Client()
+	ctx := context.Background()
+
+	data := []byte("weights")
+	sum := sha256.Sum256(data)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+
+	if exists, err := client.CheckBlobExists(ctx, digest); err != nil || exists {
+		t.Fatalf("blob exists before upload: %v, %v", exists, err)
+	}
+
+	if err := client.PushBlob(ctx, digest, bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+
+	if stored, ok := srv.Blob(digest); !ok || !bytes.Equal(stored, data) {
+		t.Errorf("stored blob = %q, %v", stored, ok)
+	}
+
+	if exists, err := client.CheckBlobExists(ctx, digest); err != nil || !exists {
+		t.Errorf("blob missing after upload: %v, %v", exists, err)
+	}
+
+	if err := client.PushBlob(ctx, digest, strings.NewReader("tampered")); err == nil {
+		t.Error("a blob not matching its digest was accepted")
+	}
+}
+
+func TestServerEnqueue(t *testing.T) {
+	srv := golloomtest.NewServer()
+	defer srv.Close()
+
+	srv.Enqueue(
+		"/api/chat",
+		golloomtest.ChatResponse("first"),
+		golloomtest.ErrorResponse(http.StatusInternalServerError, "boom"),
+	)
+
+	client := srv.Client()
+	ctx := context.Background()
+	req := &golloom.Chat{
+		Model:    "llama3",
+		Messages: []golloom.Message{{Role: "user", Content: "echo"}},
+	}
+
+	if resp, err := client.Chat(ctx, req); err != nil || resp.Message.Content != "first" {
+		t.Fatalf("first reply = %+v, %v", resp, err)
+	}
+
+	var apiErr *golloom.APIError
+	if _, err := client.Chat(ctx, req); !errors.As(err, &apiErr) || apiErr.Message != "boom" {
+		t.Fatalf("second reply error = %v, want the scripted error", err)
+	}
+
+	if resp, err := client.Chat(ctx, req); err != nil || resp.Message.Content != "echo" {
+		t.Fatalf("third reply = %+v, %v; want the built-in echo", resp, err)
+	}
+
+	if got := len(srv.RequestsTo("/api/chat")); got != 3 {
+		t.Errorf("recorded %d chat requests, want 3", got)
+	}
+
+	last, ok := srv.LastRequest("/api/chat")
+	if !ok || last.Method != http.MethodPost {
+		t.Errorf("last request = %+v, %v", last, ok)
+	}
+
+	srv.Reset()
+	if got := len(srv.Requests()); got != 0 {
+		t.Errorf("%d requests left after Reset", got)
+	}
+}
+
+func TestServerStreamError(t *testing.T) {
+	srv := golloomtest.NewServer()
+	defer srv.Close()
+
+	srv.Enqueue("/api/chat", golloomtest.Response{
+		Stream: []interface{}{
+			&golloom.ModelResponse{Message: golloom.Message{Role: "assistant", Content: "partial"}},
+			golloomtest.ErrorLine("out of memory"),
+		},
+	})
+
+	req := &golloom.Chat{Model: "llama3"}
+
+	var got []string
+	var streamErr error
+
+	for chunk, err := range srv.Client().ChatStream(context.Background(), req) {
+		if err != nil {
+			streamErr = err
+			break
+		}
+
+		got = append(got, chunk.Message.Content)
+	}
+
+	if len(got) != 1 || got[0] != "partial" {
+		t.Errorf("chunks before the error = %q", got)
+	}
+
+	var apiErr *golloom.APIError
+	if !errors.As(streamErr, &apiErr) || apiErr.Message != "out of memory" {
+		t.Errorf("stream error = %v, want the scripted error line", streamErr)
+	}
+}
+
+func TestServerHandleAndDelay(t *testing.T) {
+	srv := golloomtest.NewServer()
+	defer srv.Close()
+
+	srv.Handle("/api/version", func(*golloomtest.Request) golloomtest.Response {
+		return golloomtest.Response{
+			Body:  &golloom.Version{Version: "custom"},
+			Delay: time.Hour,
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := srv.Client().Version(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+}
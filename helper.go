@@ -18,16 +18,18 @@
 package golloom
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"iter"
 	"net/http"
 )
 
 // sendRequest constructs and sends an HTTP request with the specified method, URL, and body.
 // It encodes the body as JSON, sets appropriate headers, and processes the server's response.
+// When the server streams its reply, every chunk is merged so the returned result is complete.
 // Parameters:
 //   - ctx: A context.Context for managing request deadlines and cancellations.
 //   - method: The HTTP method (e.g., "GET", "POST") to use for the request.
@@ -42,49 +44,33 @@ func (c *Client) sendRequest(
 	method, urlStr string,
 	body interface{},
 ) (*PromptResult, error) {
-	buf := new(bytes.Buffer)
-	if err := json.NewEncoder(buf).Encode(body); err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequestWithContext(
+	resp, err := c.sendStreamRequest(
 		ctx,
 		method,
 		urlStr,
-		buf,
+		body,
 	)
 
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := c.HTTPClient.Do(req)
-
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		errorBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf(
-			"HTTP request failed with status %d: %s",
-			resp.StatusCode,
-			errorBody,
-		)
-	}
+	genResp := &PromptResult{}
+	for chunk, err := range decodeStream[PromptResult](ctx, resp) {
+		if err != nil {
+			return nil, err
+		}
 
-	var genResp PromptResult
-	if err := json.NewDecoder(resp.Body).Decode(&genResp); err != nil {
-		return nil, err
+		genResp.merge(chunk)
 	}
 
-	return &genResp, nil
+	return genResp, nil
 }
 
 // sendChatRequest functions similarly to sendRequest but expects a response of type ModelResponse.
 // It constructs and sends an HTTP request with the specified method, URL, and body, then decodes the response.
+// When the server streams its reply, every chunk is merged so the returned message is complete.
 // Parameters:
 //   - ctx: A context.Context for managing request deadlines and cancellations.
 //   - method: The HTTP method to use for the request.
@@ -99,36 +85,27 @@ func (c *Client) sendChatRequest(
 	method, urlStr string,
 	body interface{},
 ) (*ModelResponse, error) {
-	buf := new(bytes.Buffer)
-	if err := json.NewEncoder(buf).Encode(body); err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequestWithContext(
+	resp, err := c.sendStreamRequest(
 		ctx,
 		method, urlStr,
-		buf,
+		body,
 	)
 
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := c.HTTPClient.Do(req)
-
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	var chatResp ModelResponse
-	err = json.NewDecoder(resp.Body).Decode(&chatResp)
-	if err != nil {
-		return nil, err
+	chatResp := &ModelResponse{}
+	for chunk, err := range decodeStream[ModelResponse](ctx, resp) {
+		if err != nil {
+			return nil, err
+		}
+
+		chatResp.merge(chunk)
 	}
 
-	return &chatResp, nil
+	return chatResp, nil
 }
 
 // sendShowRequest constructs and sends an HTTP request, expecting a response of type ModelInfoResult.
@@ -147,24 +124,22 @@ func (c *Client) sendShowRequest(
 	method, urlStr string,
 	body interface{},
 ) (*ModelInfoResult, error) {
-	buf := new(bytes.Buffer)
-	if err := json.NewEncoder(buf).Encode(body); err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequestWithContext(ctx, method, urlStr, buf)
+	req, err := c.newJSONRequest(ctx, method, urlStr, body)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.doBuffered(req)
 
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+
 	var showResp ModelInfoResult
 	err = json.NewDecoder(resp.Body).Decode(&showResp)
 	if err != nil {
@@ -190,24 +165,22 @@ func (c *Client) sendEmbedRequest(
 	method, urlStr string,
 	body interface{},
 ) (*EmbedResult, error) {
-	buf := new(bytes.Buffer)
-	if err := json.NewEncoder(buf).Encode(body); err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequestWithContext(ctx, method, urlStr, buf)
+	req, err := c.newJSONRequest(ctx, method, urlStr, body)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.doBuffered(req)
 
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+
 	var embedResp EmbedResult
 	err = json.NewDecoder(resp.Body).Decode(&embedResp)
 	if err != nil {
@@ -218,7 +191,7 @@ func (c *Client) sendEmbedRequest(
 }
 
 // sendStatusStreamRequest constructs and sends an HTTP request to a specified URL with the given method and body.
-// It expects a streaming response containing status messages and processes them accordingly.
+// It expects a streaming response containing status messages and collects them without any cap on their number.
 // Parameters:
 //   - ctx: A context.Context for managing request deadlines and cancellations.
 //   - method: The HTTP method (e.g., "POST") to use for the request.
@@ -227,7 +200,7 @@ func (c *Client) sendEmbedRequest(
 //
 // Returns:
 //   - A pointer to a struct containing a slice of status messages received from the server.
-//   - An error if the request fails, the response cannot be processed, or if the number of status messages exceeds the maximum allowed.
+//   - An error if the request fails, the response cannot be processed, or the stream reports an error.
 func (c *Client) sendStatusStreamRequest(
 	ctx context.Context,
 	method, urlStr string,
@@ -238,60 +211,116 @@ func (c *Client) sendStatusStreamRequest(
 	},
 	error,
 ) {
-	buf := new(bytes.Buffer)
-	if err := json.NewEncoder(buf).Encode(body); err != nil {
+	msgs, err := c.sendProgressStreamRequest(
+		ctx,
+		method,
+		urlStr,
+		body,
+		nil,
+	)
+
+	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequestWithContext(
+	return &struct {
+		StatusMessages []string `json:"status_messages"`
+	}{StatusMessages: msgs}, nil
+}
+
+// sendStreamRequest constructs and sends an HTTP request whose response body is consumed incrementally.
+// It encodes the body as JSON, checks the status code, and hands the open response back to the caller.
+// Parameters:
+//   - ctx: A context.Context for managing request deadlines and cancellations.
+//   - method: The HTTP method (e.g., "POST") to use for the request.
+//   - urlStr: The target URL as a string.
+//   - body: The payload to be sent with the request; it will be JSON-encoded.
+//
+// Returns:
+//   - A pointer to the http.Response whose Body must be closed by the caller.
+//   - An error if the request fails, or an *APIError if the server responds with a non-2xx status.
+func (c *Client) sendStreamRequest(
+	ctx context.Context,
+	method, urlStr string,
+	body interface{},
+) (*http.Response, error) {
+	req, err := c.newJSONRequest(
 		ctx,
 		method,
 		urlStr,
-		buf,
+		body,
 	)
 
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.do(req)
 
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		limitedBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
-		return nil, fmt.Errorf(
-			"HTTP request failed with status %d: %s",
-			resp.StatusCode,
-			string(limitedBody),
-		)
+	if err := checkResponse(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
 	}
 
-	var msgs []string
-	maxMessages := 1000
-
-	dec := json.NewDecoder(resp.Body)
-	for dec.More() && len(msgs) < maxMessages {
-		var s struct {
-			Status string `json:"status"`
-		}
+	return resp, nil
+}
 
-		if err := dec.Decode(&s); err != nil {
-			return nil, fmt.Errorf("error decoding stream: %w", err)
+// decodeStream reads the newline-delimited JSON body of resp and yields every object decoded as T.
+// A line carrying an "error" field terminates the stream with an *APIError, and a read failure
+// caused by cancellation is reported as the context's error.
+// Parameters:
+//   - ctx: The context.Context governing the underlying request.
+//   - resp: The response whose body provides the NDJSON stream.
+//
+// Returns:
+//   - An iterator over the decoded chunks and any error that ended the stream.
+func decodeStream[T any](
+	ctx context.Context,
+	resp *http.Response,
+) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		dec := json.NewDecoder(resp.Body)
+		for {
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				if errors.Is(err, io.EOF) {
+					return
+				}
+
+				if ctxErr := ctx.Err(); ctxErr != nil {
+					err = ctxErr
+				}
+
+				yield(nil, fmt.Errorf("error decoding stream: %w", err))
+				return
+			}
+
+			var streamErr struct {
+				Error string `json:"error"`
+			}
+
+			if err := json.Unmarshal(raw, &streamErr); err == nil && streamErr.Error != "" {
+				yield(nil, &APIError{
+					StatusCode: resp.StatusCode,
+					Message:    streamErr.Error,
+					Endpoint:   responseEndpoint(resp),
+				})
+				return
+			}
+
+			var chunk T
+			if err := json.Unmarshal(raw, &chunk); err != nil {
+				yield(nil, fmt.Errorf("error decoding stream: %w", err))
+				return
+			}
+
+			if !yield(&chunk, nil) {
+				return
+			}
 		}
-
-		msgs = append(msgs, s.Status)
-	}
-
-	if len(msgs) >= maxMessages {
-		return nil, fmt.Errorf("streaming response exceeded maximum allowed messages")
 	}
-
-	return &struct {
-		StatusMessages []string `json:"status_messages"`
-	}{StatusMessages: msgs}, nil
 }
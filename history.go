@@ -0,0 +1,302 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloom
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// HistoryStrategy selects what a HistoryPolicy does with turns that no longer fit the context window.
+type HistoryStrategy int
+
+const (
+	// HistoryDrop discards the oldest turns.
+	HistoryDrop HistoryStrategy = iota
+	// HistorySummarize replaces the oldest turns with a summary produced by a secondary model call.
+	HistorySummarize
+)
+
+// HistoryPolicy keeps a Conversation within the context window of its model. Before every turn
+// the size of the prompt is estimated and, when it exceeds the budget, the oldest turns are dropped
+// or summarized. The system prompt is always kept.
+type HistoryPolicy struct {
+	// Strategy chooses between dropping and summarizing old turns.
+	Strategy HistoryStrategy
+	// ContextLength is the context window in tokens. When zero, it is taken from the "num_ctx"
+	// option of the conversation, or else from the model's "<architecture>.context_length" metadata.
+	ContextLength int
+	// ReserveTokens is the part of the context window kept free for the reply.
+	// When zero, a quarter of the context window is reserved.
+	ReserveTokens int
+	// KeepRecent is the minimum number of most recent messages that are never evicted.
+	// When zero, only the newest message is guaranteed to be kept.
+	KeepRecent int
+	// SummaryModel is the model used to summarize old turns; it defaults to the conversation's model.
+	SummaryModel string
+	// CharsPerToken is the ratio used to estimate token counts before the server has reported any
+	// prompt_eval_count. When zero, 4 characters per token is assumed.
+	CharsPerToken float64
+}
+
+// summaryPrompt instructs the secondary model how to condense evicted turns.
+const summaryPrompt = "Summarize the following conversation concisely. " +
+	"Preserve facts, names, decisions, and open questions that later replies may depend on. " +
+	"Reply with the summary only."
+
+// SetHistoryPolicy sets the policy used to keep the history within the model's context window.
+// Passing nil disables history management, leaving truncation to the server.
+func (cv *Conversation) SetHistoryPolicy(policy *HistoryPolicy) {
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+
+	cv.historyPolicy = policy
+}
+
+// Summary returns the summary standing in for turns evicted by a HistorySummarize policy, if any.
+func (cv *Conversation) Summary() string {
+	cv.mu.RLock()
+	defer cv.mu.RUnlock()
+
+	return cv.summary
+}
+
+// fitHistory evicts the oldest messages until the estimated prompt fits the policy's budget,
+// summarizing them first when the policy asks for it. It is called with cv.sendMu held.
+func (cv *Conversation) fitHistory(
+	ctx context.Context,
+	policy *HistoryPolicy,
+) error {
+	contextLength, err := cv.resolveContextLength(ctx, policy)
+	if err != nil {
+		return err
+	}
+
+	reserve := policy.ReserveTokens
+	if reserve <= 0 {
+		reserve = contextLength / 4
+	}
+
+	budget := contextLength - reserve
+	keep := max(policy.KeepRecent, 1)
+
+	cv.mu.RLock()
+	messages := append([]Message(nil), cv.messages...)
+	prefix := cv.pinned()
+	cv.mu.RUnlock()
+
+	if cv.estimateTokens(policy, prefix, messages) <= budget {
+		return nil
+	}
+
+	evict := 0
+	for evict < len(messages)-keep {
+		evict++
+		for evict < len(messages)-keep && messages[evict].Role == "tool" {
+			evict++
+		}
+
+		if cv.estimateTokens(policy, prefix, messages[evict:]) <= budget {
+			break
+		}
+	}
+
+	if evict == 0 {
+		return nil
+	}
+
+	summary := ""
+	if policy.Strategy == HistorySummarize {
+		summary, err = cv.summarize(ctx, policy, messages[:evict])
+		if err != nil {
+			return fmt.Errorf("summarizing history: %w", err)
+		}
+	}
+
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+
+	if len(cv.messages) >= evict {
+		cv.messages = append([]Message(nil), cv.messages[evict:]...)
+	}
+
+	if policy.Strategy == HistorySummarize {
+		cv.summary = summary
+	}
+
+	return nil
+}
+
+// summarize asks the summary model to condense evicted messages, folding in any earlier summary.
+func (cv *Conversation) summarize(
+	ctx context.Context,
+	policy *HistoryPolicy,
+	evicted []Message,
+) (string, error) {
+	cv.mu.RLock()
+	model := cv.model
+	previous := cv.summary
+	keepAlive := cv.keepAlive
+	cv.mu.RUnlock()
+
+	if policy.SummaryModel != "" {
+		model = policy.SummaryModel
+	}
+
+	var transcript strings.Builder
+	if previous != "" {
+		fmt.Fprintf(&transcript, "Earlier summary:\n%s\n\n", previous)
+	}
+
+	for _, msg := range evicted {
+		fmt.Fprintf(&transcript, "%s: %s\n", msg.Role, msg.Content)
+	}
+
+	resp, err := cv.client.Chat(ctx, &Chat{
+		Model: model,
+		Messages: []Message{
+			{Role: "system", Content: summaryPrompt},
+			{Role: "user", Content: transcript.String()},
+		},
+		KeepAlive: keepAlive,
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(resp.Message.Content), nil
+}
+
+// resolveContextLength determines the context window from the policy, the conversation's
+// "num_ctx" option, or the model metadata returned by FetchModelInfo, caching the latter per model.
+func (cv *Conversation) resolveContextLength(
+	ctx context.Context,
+	policy *HistoryPolicy,
+) (int, error) {
+	if policy.ContextLength > 0 {
+		return policy.ContextLength, nil
+	}
+
+	cv.mu.RLock()
+	model := cv.model
+	numCtx, hasNumCtx := intOption(cv.options["num_ctx"])
+	cached, hasCached := cv.contextLengths[model]
+	cv.mu.RUnlock()
+
+	if hasNumCtx && numCtx > 0 {
+		return numCtx, nil
+	}
+
+	if hasCached {
+		return cached, nil
+	}
+
+	info, err := cv.client.FetchModelInfo(ctx, model, false)
+	if err != nil {
+		return 0, fmt.Errorf("fetching context length of %s: %w", model, err)
+	}
+
+	length, ok := info.ContextLength()
+	if !ok {
+		return 0, fmt.Errorf("model %s does not report a context length", model)
+	}
+
+	cv.mu.Lock()
+	if cv.contextLengths == nil {
+		cv.contextLengths = make(map[string]int)
+	}
+	cv.contextLengths[model] = length
+	cv.mu.Unlock()
+
+	return length, nil
+}
+
+// estimateTokens estimates the prompt size of the given messages. Once the server has reported a
+// prompt_eval_count, the observed tokens-per-character ratio of the previous turn is used; before
+// that, the policy's CharsPerToken ratio applies.
+func (cv *Conversation) estimateTokens(
+	policy *HistoryPolicy,
+	groups ...[]Message,
+) int {
+	chars := 0
+	for _, group := range groups {
+		chars += messageChars(group)
+	}
+
+	cv.mu.RLock()
+	tokens, sampleChars := cv.lastPromptTokens, cv.lastPromptChars
+	cv.mu.RUnlock()
+
+	if tokens > 0 && sampleChars > 0 {
+		return chars * tokens / sampleChars
+	}
+
+	charsPerToken := policy.CharsPerToken
+	if charsPerToken <= 0 {
+		charsPerToken = 4
+	}
+
+	return int(float64(chars) / charsPerToken)
+}
+
+// recordPromptSize remembers the prompt_eval_count reported for req so later estimates can use
+// the observed ratio. The caller must hold cv.mu.
+func (cv *Conversation) recordPromptSize(
+	req *Chat,
+	resp *ModelResponse,
+) {
+	if resp.PromptEvalCount <= 0 {
+		return
+	}
+
+	cv.lastPromptTokens = resp.PromptEvalCount
+	cv.lastPromptChars = messageChars(req.Messages)
+}
+
+// messageChars approximates the size of messages in characters, including a small
+// allowance per message for the role markers added by chat templates.
+func messageChars(messages []Message) int {
+	const perMessage = 8
+
+	chars := 0
+	for _, msg := range messages {
+		chars += len(msg.Content) + perMessage
+	}
+
+	return chars
+}
+
+// intOption converts a numeric option value, as found in maps decoded from JSON or built by hand, to an int.
+func intOption(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int32:
+		return int(n), true
+	case int64:
+		return int(n), true
+	case float64:
+		return int(n), true
+	case float32:
+		return int(n), true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,283 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloom_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/nthnn/golloom"
+	"github.com/nthnn/golloom/golloomtest"
+)
+
+// longTurns returns n alternating user and assistant messages of 40 characters each,
+// about 12 tokens apiece at the default ratio of 4 characters per token.
+func longTurns(n int) []golloom.Message {
+	messages := make([]golloom.Message, n)
+	for i := range messages {
+		role := "user"
+		if i%2 == 1 {
+			role = "assistant"
+		}
+
+		messages[i] = golloom.Message{
+			Role:    role,
+			Content: strings.Repeat(string(rune('a'+i)), 40),
+		}
+	}
+
+	return messages
+}
+
+// lastChat decodes the last chat request received by srv.
+func lastChat(t *testing.T, srv *golloomtest.Server) golloom.Chat {
+	t.Helper()
+
+	last, ok := srv.LastRequest("/api/chat")
+	if !ok {
+		t.Fatal("no chat request was sent")
+	}
+
+	var sent golloom.Chat
+	if err := last.Decode(&sent); err != nil {
+		t.Fatal(err)
+	}
+
+	return sent
+}
+
+// showContextLength makes srv report the given context length for model and no other.
+func showContextLength(
+	srv *golloomtest.Server,
+	length int,
+	model string,
+) {
+	srv.Handle("/api/show", func(req *golloomtest.Request) golloomtest.Response {
+		var body struct {
+			Model string `json:"model"`
+		}
+
+		if err := req.Decode(&body); err != nil || body.Model != model {
+			return golloomtest.ErrorResponse(http.StatusNotFound, "model '"+body.Model+"' not found")
+		}
+
+		return golloomtest.Response{
+			Body: map[string]interface{}{
+				"model_info": map[string]interface{}{
+					"general.architecture": "llama",
+					"llama.context_length": length,
+				},
+			},
+		}
+	})
+}
+
+func TestHistoryDrop(t *testing.T) {
+	srv := golloomtest.NewServer()
+	defer srv.Close()
+
+	cv := golloom.NewConversation(srv.Client(), "llama3", "Be brief.")
+	cv.SetHistoryPolicy(&golloom.HistoryPolicy{
+		Strategy:      golloom.HistoryDrop,
+		ContextLength: 60,
+		ReserveTokens: 10,
+	})
+
+	cv.Append(longTurns(6)...)
+
+	if _, err := cv.Send(context.Background(), "hi"); err != nil {
+		t.Fatal(err)
+	}
+
+	sent := lastChat(t, srv)
+	if sent.Messages[0].Role != "system" || sent.Messages[0].Content != "Be brief." {
+		t.Errorf("first sent message = %+v, want the system prompt", sent.Messages[0])
+	}
+
+	if len(sent.Messages) >= 8 {
+		t.Errorf("sent %d messages; nothing was dropped", len(sent.Messages))
+	}
+
+	if got := sent.Messages[len(sent.Messages)-1].Content; got != "hi" {
+		t.Errorf("last sent message = %q, want the new turn", got)
+	}
+
+	if got := len(srv.RequestsTo("/api/chat")); got != 1 {
+		t.Errorf("chat requests = %d; dropping must not call the model", got)
+	}
+
+	if cv.Summary() != "" {
+		t.Errorf("summary = %q, want none", cv.Summary())
+	}
+}
+
+func TestHistoryKeepRecent(t *testing.T) {
+	srv := golloomtest.NewServer()
+	defer srv.Close()
+
+	cv := golloom.NewConversation(srv.Client(), "llama3", "")
+	cv.SetHistoryPolicy(&golloom.HistoryPolicy{
+		ContextLength: 10,
+		ReserveTokens: 5,
+		KeepRecent:    3,
+	})
+
+	turns := longTurns(6)
+	cv.Append(turns...)
+
+	if _, err := cv.Send(context.Background(), "hi"); err != nil {
+		t.Fatal(err)
+	}
+
+	sent := lastChat(t, srv)
+	if len(sent.Messages) != 3 {
+		t.Fatalf("sent %d messages, want the 3 most recent", len(sent.Messages))
+	}
+
+	if sent.Messages[0].Content != turns[4].Content || sent.Messages[2].Content != "hi" {
+		t.Errorf("sent messages = %+v", sent.Messages)
+	}
+}
+
+func TestHistorySkipsOrphanToolMessages(t *testing.T) {
+	srv := golloomtest.NewServer()
+	defer srv.Close()
+
+	cv := golloom.NewConversation(srv.Client(), "llama3", "")
+	cv.SetHistoryPolicy(&golloom.HistoryPolicy{
+		ContextLength: 30,
+		ReserveTokens: 1,
+	})
+
+	long := strings.Repeat("x", 40)
+	cv.Append(
+		golloom.Message{Role: "user", Content: long},
+		golloom.Message{Role: "assistant", Content: long},
+		golloom.Message{Role: "tool", Content: long},
+		golloom.Message{Role: "tool", Content: "5"},
+		golloom.Message{Role: "assistant", Content: "done"},
+	)
+
+	if _, err := cv.Send(context.Background(), "next"); err != nil {
+		t.Fatal(err)
+	}
+
+	sent := lastChat(t, srv)
+	if sent.Messages[0].Role == "tool" {
+		t.Errorf("history starts with an orphaned tool message: %+v", sent.Messages)
+	}
+
+	if len(sent.Messages) != 2 || sent.Messages[0].Content != "done" {
+		t.Errorf("sent messages = %+v, want the final answer and the new turn", sent.Messages)
+	}
+}
+
+func TestHistorySummarize(t *testing.T) {
+	srv := golloomtest.NewServer()
+	defer srv.Close()
+
+	srv.Enqueue("/api/chat", golloomtest.ChatResponse("  they talked about letters  "))
+
+	cv := golloom.NewConversation(srv.Client(), "llama3", "Be brief.")
+	cv.SetHistoryPolicy(&golloom.HistoryPolicy{
+		Strategy:      golloom.HistorySummarize,
+		ContextLength: 60,
+		ReserveTokens: 10,
+		SummaryModel:  "summarizer",
+	})
+
+	cv.Append(longTurns(6)...)
+
+	if _, err := cv.Send(context.Background(), "hi"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := cv.Summary(); got != "they talked about letters" {
+		t.Errorf("summary = %q", got)
+	}
+
+	chats := srv.RequestsTo("/api/chat")
+	if len(chats) != 2 {
+		t.Fatalf("chat requests = %d, want the summary and the turn", len(chats))
+	}
+
+	var summaryReq golloom.Chat
+	if err := chats[0].Decode(&summaryReq); err != nil {
+		t.Fatal(err)
+	}
+
+	if summaryReq.Model != "summarizer" || !strings.Contains(summaryReq.Messages[1].Content, strings.Repeat("a", 40)) {
+		t.Errorf("summary request = %+v, want the oldest turn sent to the summary model", summaryReq)
+	}
+
+	sent := lastChat(t, srv)
+	if len(sent.Messages) < 3 || sent.Messages[0].Content != "Be brief." ||
+		sent.Messages[1].Role != "system" || !strings.Contains(sent.Messages[1].Content, "they talked about letters") {
+		t.Errorf("sent messages = %+v, want the system prompt followed by the summary", sent.Messages)
+	}
+}
+
+func TestHistoryContextLengthLookup(t *testing.T) {
+	srv := golloomtest.NewServer()
+	defer srv.Close()
+
+	showContextLength(srv, 60, "llama3")
+
+	cv := golloom.NewConversation(srv.Client(), "llama3", "")
+	cv.SetHistoryPolicy(&golloom.HistoryPolicy{ReserveTokens: 10})
+	cv.SetOptions(map[string]interface{}{"num_ctx": float64(100000)})
+	cv.Append(longTurns(6)...)
+
+	ctx := context.Background()
+	if _, err := cv.Send(ctx, "hi"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := len(srv.RequestsTo("/api/show")); got != 0 {
+		t.Errorf("show requests = %d; num_ctx must take precedence", got)
+	}
+
+	if got := len(lastChat(t, srv).Messages); got != 7 {
+		t.Errorf("sent %d messages, want all 7 within num_ctx", got)
+	}
+
+	cv.SetOptions(nil)
+
+	for i := 0; i < 2; i++ {
+		if _, err := cv.Send(ctx, "hi"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := len(srv.RequestsTo("/api/show")); got != 1 {
+		t.Errorf("show requests = %d, want the context length fetched once and cached", got)
+	}
+
+	if got := len(lastChat(t, srv).Messages); got >= 9 {
+		t.Errorf("sent %d messages; the model's context length was not applied", got)
+	}
+
+	missing := golloom.NewConversation(srv.Client(), "missing", "")
+	missing.SetHistoryPolicy(&golloom.HistoryPolicy{})
+
+	if _, err := missing.Send(ctx, "hi"); err == nil || !strings.Contains(err.Error(), "context length") {
+		t.Errorf("err = %v, want a context length lookup error", err)
+	}
+}
@@ -19,17 +19,174 @@ package golloom
 
 import (
 	"context"
+	"fmt"
 	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Capability names a feature a model supports, as listed in ModelInfoResult.Capabilities.
+type Capability string
+
+const (
+	CapabilityCompletion Capability = "completion" // The model generates text.
+	CapabilityVision     Capability = "vision"     // The model accepts images.
+	CapabilityTools      Capability = "tools"      // The model can call tools.
+	CapabilityEmbedding  Capability = "embedding"  // The model produces embeddings.
+	CapabilityInsert     Capability = "insert"     // The model fills in text between a prompt and a suffix.
+	CapabilityThinking   Capability = "thinking"   // The model can reason before answering.
 )
 
 // ModelInfoResult represents the structure of the response returned by the FetchModelInfo method.
 // It includes various details about a model, such as its configuration, parameters, and additional metadata.
 type ModelInfoResult struct {
-	Modelfile  string                 `json:"modelfile"`  // The content or path of the model file associated with the model.
-	Parameters string                 `json:"parameters"` // A string representation of the model's parameters.
-	Template   string                 `json:"template"`   // The template used for the model, possibly indicating its architecture or purpose.
-	Details    map[string]interface{} `json:"details"`    // A map containing additional detailed information about the model.
-	ModelInfo  map[string]interface{} `json:"model_info"` // A map containing metadata or attributes specific to the model.
+	Modelfile    string                 `json:"modelfile"`              // The content or path of the model file associated with the model.
+	Parameters   string                 `json:"parameters"`             // A string representation of the model's parameters; see Options for the parsed form.
+	Template     string                 `json:"template"`               // The template used for the model, possibly indicating its architecture or purpose.
+	License      string                 `json:"license,omitempty"`      // The license text of the model, if any.
+	Details      ModelDetails           `json:"details"`                // The format, family, parameter size, and quantization level of the model.
+	ModelInfo    map[string]interface{} `json:"model_info"`             // A map containing metadata or attributes specific to the model.
+	Capabilities []Capability           `json:"capabilities,omitempty"` // The features the model supports.
+	ModifiedAt   time.Time              `json:"modified_at"`            // The timestamp indicating the last modification time of the model.
+}
+
+// Options parses Parameters, the default runtime parameters of the model, into a ModelOptions.
+// Each line holds a parameter name followed by its value, with strings in double quotes;
+// repeated "stop" lines accumulate.
+// Returns:
+//   - A pointer to the parsed ModelOptions; empty when the model sets no parameters.
+//   - An error if a line is malformed, names an unknown parameter, or holds an invalid value.
+func (r *ModelInfoResult) Options() (*ModelOptions, error) {
+	opts := NewModelOptions()
+	for _, line := range strings.Split(r.Parameters, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(line, " ")
+		if !ok {
+			return nil, fmt.Errorf("malformed parameter line %q", line)
+		}
+
+		value = strings.TrimSpace(value)
+		if strings.HasPrefix(value, `"`) {
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return nil, fmt.Errorf("malformed parameter line %q", line)
+			}
+
+			value = unquoted
+		}
+
+		if err := opts.SetParameter(name, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return opts, nil
+}
+
+// HasCapability reports whether the model lists the given capability.
+func (r *ModelInfoResult) HasCapability(capability Capability) bool {
+	for _, c := range r.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SupportsCompletion reports whether the model generates text.
+func (r *ModelInfoResult) SupportsCompletion() bool {
+	return r.HasCapability(CapabilityCompletion)
+}
+
+// SupportsVision reports whether the model accepts images.
+func (r *ModelInfoResult) SupportsVision() bool {
+	return r.HasCapability(CapabilityVision)
+}
+
+// SupportsTools reports whether the model can call tools.
+func (r *ModelInfoResult) SupportsTools() bool {
+	return r.HasCapability(CapabilityTools)
+}
+
+// SupportsEmbedding reports whether the model produces embeddings.
+func (r *ModelInfoResult) SupportsEmbedding() bool {
+	return r.HasCapability(CapabilityEmbedding)
+}
+
+// SupportsInsert reports whether the model fills in text between a prompt and a suffix.
+func (r *ModelInfoResult) SupportsInsert() bool {
+	return r.HasCapability(CapabilityInsert)
+}
+
+// SupportsThinking reports whether the model can reason before answering.
+func (r *ModelInfoResult) SupportsThinking() bool {
+	return r.HasCapability(CapabilityThinking)
+}
+
+// Architecture returns the architecture reported in ModelInfo, e.g. "llama", or an empty string.
+func (r *ModelInfoResult) Architecture() string {
+	arch, _ := r.ModelInfo["general.architecture"].(string)
+	return arch
+}
+
+// ContextLength returns the context window the model was trained with, in tokens.
+// The boolean result is false if ModelInfo does not report it.
+func (r *ModelInfoResult) ContextLength() (int, bool) {
+	return r.architectureInt("context_length")
+}
+
+// EmbeddingLength returns the size of the model's embedding vectors.
+// The boolean result is false if ModelInfo does not report it.
+func (r *ModelInfoResult) EmbeddingLength() (int, bool) {
+	return r.architectureInt("embedding_length")
+}
+
+// HeadCount returns the number of attention heads of the model.
+// The boolean result is false if ModelInfo does not report it as a single number.
+func (r *ModelInfoResult) HeadCount() (int, bool) {
+	return r.architectureInt("attention.head_count")
+}
+
+// VocabSize returns the number of tokens in the model's vocabulary. It is read from the
+// architecture's "vocab_size" key or, failing that, counted from the tokenizer's token list,
+// which is only included when FetchModelInfo is called with verbose set.
+// The boolean result is false if neither is available.
+func (r *ModelInfoResult) VocabSize() (int, bool) {
+	if size, ok := r.architectureInt("vocab_size"); ok {
+		return size, true
+	}
+
+	if tokens, ok := r.ModelInfo["tokenizer.ggml.tokens"].([]interface{}); ok && len(tokens) > 0 {
+		return len(tokens), true
+	}
+
+	return 0, false
+}
+
+// architectureInt reads the integer stored under "<architecture>.<key>" in ModelInfo, falling back
+// to any key ending in ".<key>" when the architecture or its key is missing.
+func (r *ModelInfoResult) architectureInt(key string) (int, bool) {
+	if arch := r.Architecture(); arch != "" {
+		if n, ok := intOption(r.ModelInfo[arch+"."+key]); ok {
+			return n, true
+		}
+	}
+
+	for k, v := range r.ModelInfo {
+		if strings.HasSuffix(k, "."+key) {
+			if n, ok := intOption(v); ok {
+				return n, true
+			}
+		}
+	}
+
+	return 0, false
 }
 
 // FetchModelInfo retrieves information about a specific model from the server.
@@ -50,7 +207,7 @@ func (c *Client) FetchModelInfo(
 	verbose bool,
 ) (*ModelInfoResult, error) {
 	rel := &url.URL{Path: "/api/show"}
-	u := c.BaseURL.ResolveReference(rel)
+	u := c.resolve(rel)
 
 	return c.sendShowRequest(
 		ctx,
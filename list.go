@@ -20,7 +20,6 @@ package golloom
 import (
 	"context"
 	"encoding/json"
-	"net/http"
 	"net/url"
 	"time"
 )
@@ -28,11 +27,12 @@ import (
 // ModelDetails encapsulates detailed attributes of a machine learning model,
 // providing insights into its format, family, parameter size, and quantization level.
 type ModelDetails struct {
-	Format            string   `json:"format"`             // The storage format of the model (e.g., ONNX, TensorFlow, PyTorch).
-	Family            string   `json:"family"`             // The primary category or group the model belongs to (e.g., Vision, NLP).
-	Families          []string `json:"families,omitempty"` // Additional categories or groups the model is associated with.
-	ParameterSize     string   `json:"parameter_size"`     // The total number of parameters in the model, often indicative of its complexity.
-	QuantizationLevel string   `json:"quantization_level"` // The degree of quantization applied to the model, affecting its size and performance.
+	ParentModel       string   `json:"parent_model,omitempty"` // The model this one was created from, if any.
+	Format            string   `json:"format"`                 // The storage format of the model (e.g., ONNX, TensorFlow, PyTorch).
+	Family            string   `json:"family"`                 // The primary category or group the model belongs to (e.g., Vision, NLP).
+	Families          []string `json:"families,omitempty"`     // Additional categories or groups the model is associated with.
+	ParameterSize     string   `json:"parameter_size"`         // The total number of parameters in the model, often indicative of its complexity.
+	QuantizationLevel string   `json:"quantization_level"`     // The degree of quantization applied to the model, affecting its size and performance.
 }
 
 // ModelInfo provides metadata about a specific machine learning model,
@@ -62,9 +62,9 @@ type ModelList struct {
 //   - An error if the request fails or the response cannot be processed.
 func (c *Client) ListModels(ctx context.Context) (*ModelList, error) {
 	rel := &url.URL{Path: "/api/tags"}
-	u := c.BaseURL.ResolveReference(rel)
+	u := c.resolve(rel)
 
-	req, err := http.NewRequestWithContext(
+	req, err := c.newRequest(
 		ctx,
 		"GET",
 		u.String(),
@@ -75,12 +75,16 @@ func (c *Client) ListModels(ctx context.Context) (*ModelList, error) {
 		return nil, err
 	}
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.doBuffered(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+
 	var listResp ModelList
 	err = json.NewDecoder(resp.Body).Decode(&listResp)
 	if err != nil {
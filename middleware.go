@@ -0,0 +1,457 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloom
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"iter"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Middleware wraps a Backend to add behaviour around its calls. A middleware typically returns
+// a struct that embeds the wrapped Backend and overrides only the methods it cares about, so
+// every other call passes straight through.
+type Middleware func(next Backend) Backend
+
+// Chain wraps backend with the given middlewares. The first middleware is the outermost,
+// so it sees every call before the others do.
+func Chain(
+	backend Backend,
+	middlewares ...Middleware,
+) Backend {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		backend = middlewares[i](backend)
+	}
+
+	return backend
+}
+
+// Logging returns a Middleware that logs every chat, generate, and embed call to logger
+// with its model, duration, and error, if any. Streamed calls are logged once the stream ends.
+func Logging(logger *slog.Logger) Middleware {
+	return func(next Backend) Backend {
+		return &loggingBackend{
+			Backend: next,
+			logger:  logger,
+		}
+	}
+}
+
+// loggingBackend is the Backend returned by Logging.
+type loggingBackend struct {
+	Backend
+	logger *slog.Logger
+}
+
+// log records the outcome of a single call.
+func (b *loggingBackend) log(
+	ctx context.Context,
+	op, model string,
+	start time.Time,
+	err error,
+) {
+	if err != nil {
+		b.logger.ErrorContext(
+			ctx,
+			"golloom "+op+" failed",
+			"model", model,
+			"duration", time.Since(start),
+			"error", err,
+		)
+
+		return
+	}
+
+	b.logger.DebugContext(
+		ctx,
+		"golloom "+op,
+		"model", model,
+		"duration", time.Since(start),
+	)
+}
+
+// Chat implements Chatter.
+func (b *loggingBackend) Chat(ctx context.Context, req *Chat) (*ModelResponse, error) {
+	start := time.Now()
+	resp, err := b.Backend.Chat(ctx, req)
+
+	b.log(ctx, "chat", req.Model, start, err)
+	return resp, err
+}
+
+// ChatStream implements Chatter.
+func (b *loggingBackend) ChatStream(ctx context.Context, req *Chat) iter.Seq2[*ModelResponse, error] {
+	return logStream(ctx, b, "chat stream", req.Model, b.Backend.ChatStream(ctx, req))
+}
+
+// Generate implements Generator.
+func (b *loggingBackend) Generate(ctx context.Context, req *PromptInfo) (*PromptResult, error) {
+	start := time.Now()
+	resp, err := b.Backend.Generate(ctx, req)
+
+	b.log(ctx, "generate", req.Model, start, err)
+	return resp, err
+}
+
+// GenerateStream implements Generator.
+func (b *loggingBackend) GenerateStream(ctx context.Context, req *PromptInfo) iter.Seq2[*PromptResult, error] {
+	return logStream(ctx, b, "generate stream", req.Model, b.Backend.GenerateStream(ctx, req))
+}
+
+// Embed implements Embedder.
+func (b *loggingBackend) Embed(
+	ctx context.Context,
+	model, input string,
+	options map[string]interface{},
+) (*EmbedResult, error) {
+	start := time.Now()
+	resp, err := b.Backend.Embed(ctx, model, input, options)
+
+	b.log(ctx, "embed", model, start, err)
+	return resp, err
+}
+
+// EmbedBatch implements Embedder.
+func (b *loggingBackend) EmbedBatch(
+	ctx context.Context,
+	model string,
+	inputs []string,
+	opts *EmbedBatchOptions,
+) (*EmbedResult, error) {
+	start := time.Now()
+	resp, err := b.Backend.EmbedBatch(ctx, model, inputs, opts)
+
+	b.log(ctx, "embed batch", model, start, err)
+	return resp, err
+}
+
+// logStream passes a stream through unchanged and logs its outcome when it ends.
+func logStream[T any](
+	ctx context.Context,
+	b *loggingBackend,
+	op, model string,
+	stream iter.Seq2[*T, error],
+) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		start := time.Now()
+
+		var streamErr error
+		defer func() {
+			b.log(ctx, op, model, start, streamErr)
+		}()
+
+		for chunk, err := range stream {
+			streamErr = err
+			if !yield(chunk, err) {
+				return
+			}
+		}
+	}
+}
+
+// Fallback returns a Middleware that retries failed chat, generate, and embed calls against
+// secondary. Streams fall back only when they fail before yielding their first chunk. Model
+// management calls always go to the wrapped Backend.
+// Parameters:
+//   - secondary: The Backend used when the wrapped one fails.
+//   - shouldFallback: Decides whether an error warrants the fallback; when nil, every error
+//     except context cancellation does.
+//
+// Returns:
+//   - The Middleware adding the fallback.
+func Fallback(
+	secondary Backend,
+	shouldFallback func(error) bool,
+) Middleware {
+	if shouldFallback == nil {
+		shouldFallback = func(err error) bool {
+			return !errors.Is(err, context.Canceled) &&
+				!errors.Is(err, context.DeadlineExceeded)
+		}
+	}
+
+	return func(next Backend) Backend {
+		return &fallbackBackend{
+			Backend:        next,
+			secondary:      secondary,
+			shouldFallback: shouldFallback,
+		}
+	}
+}
+
+// fallbackBackend is the Backend returned by Fallback.
+type fallbackBackend struct {
+	Backend
+	secondary      Backend
+	shouldFallback func(error) bool
+}
+
+// fallback reports whether a call that failed with err should be retried on the secondary backend.
+func (b *fallbackBackend) fallback(ctx context.Context, err error) bool {
+	return err != nil && ctx.Err() == nil && b.shouldFallback(err)
+}
+
+// Chat implements Chatter.
+func (b *fallbackBackend) Chat(ctx context.Context, req *Chat) (*ModelResponse, error) {
+	resp, err := b.Backend.Chat(ctx, req)
+	if b.fallback(ctx, err) {
+		return b.secondary.Chat(ctx, req)
+	}
+
+	return resp, err
+}
+
+// ChatStream implements Chatter.
+func (b *fallbackBackend) ChatStream(ctx context.Context, req *Chat) iter.Seq2[*ModelResponse, error] {
+	return fallbackStream(
+		ctx,
+		b,
+		b.Backend.ChatStream(ctx, req),
+		func() iter.Seq2[*ModelResponse, error] {
+			return b.secondary.ChatStream(ctx, req)
+		},
+	)
+}
+
+// Generate implements Generator.
+func (b *fallbackBackend) Generate(ctx context.Context, req *PromptInfo) (*PromptResult, error) {
+	resp, err := b.Backend.Generate(ctx, req)
+	if b.fallback(ctx, err) {
+		return b.secondary.Generate(ctx, req)
+	}
+
+	return resp, err
+}
+
+// GenerateStream implements Generator.
+func (b *fallbackBackend) GenerateStream(ctx context.Context, req *PromptInfo) iter.Seq2[*PromptResult, error] {
+	return fallbackStream(
+		ctx,
+		b,
+		b.Backend.GenerateStream(ctx, req),
+		func() iter.Seq2[*PromptResult, error] {
+			return b.secondary.GenerateStream(ctx, req)
+		},
+	)
+}
+
+// Embed implements Embedder.
+func (b *fallbackBackend) Embed(
+	ctx context.Context,
+	model, input string,
+	options map[string]interface{},
+) (*EmbedResult, error) {
+	resp, err := b.Backend.Embed(ctx, model, input, options)
+	if b.fallback(ctx, err) {
+		return b.secondary.Embed(ctx, model, input, options)
+	}
+
+	return resp, err
+}
+
+// EmbedBatch implements Embedder.
+func (b *fallbackBackend) EmbedBatch(
+	ctx context.Context,
+	model string,
+	inputs []string,
+	opts *EmbedBatchOptions,
+) (*EmbedResult, error) {
+	resp, err := b.Backend.EmbedBatch(ctx, model, inputs, opts)
+	if b.fallback(ctx, err) {
+		return b.secondary.EmbedBatch(ctx, model, inputs, opts)
+	}
+
+	return resp, err
+}
+
+// fallbackStream yields primary, switching to the stream returned by secondary
+// when primary fails before producing any chunk.
+func fallbackStream[T any](
+	ctx context.Context,
+	b *fallbackBackend,
+	primary iter.Seq2[*T, error],
+	secondary func() iter.Seq2[*T, error],
+) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		started := false
+		for chunk, err := range primary {
+			if err != nil && !started && b.fallback(ctx, err) {
+				break
+			}
+
+			started = true
+			if !yield(chunk, err) || err != nil {
+				return
+			}
+		}
+
+		if started {
+			return
+		}
+
+		for chunk, err := range secondary() {
+			if !yield(chunk, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Caching returns a Middleware that remembers the results of non-streamed chat, generate, and
+// embed calls, keyed by their full request, and answers repeated requests without reaching the
+// wrapped Backend. It suits embeddings and deterministic sampling such as a zero temperature.
+// Failed calls and streams are never cached.
+// Parameters:
+//   - maxEntries: The number of results kept; the oldest is evicted first. Values below 1 default to 256.
+//
+// Returns:
+//   - The Middleware adding the cache.
+func Caching(maxEntries int) Middleware {
+	if maxEntries < 1 {
+		maxEntries = 256
+	}
+
+	return func(next Backend) Backend {
+		return &cachingBackend{
+			Backend:    next,
+			maxEntries: maxEntries,
+			entries:    make(map[string]interface{}),
+		}
+	}
+}
+
+// cachingBackend is the Backend returned by Caching.
+type cachingBackend struct {
+	Backend
+
+	mu         sync.Mutex
+	maxEntries int
+	order      []string
+	entries    map[string]interface{}
+}
+
+// Chat implements Chatter.
+func (b *cachingBackend) Chat(ctx context.Context, req *Chat) (*ModelResponse, error) {
+	return cached(b, "chat", req, func() (*ModelResponse, error) {
+		return b.Backend.Chat(ctx, req)
+	})
+}
+
+// Generate implements Generator.
+func (b *cachingBackend) Generate(ctx context.Context, req *PromptInfo) (*PromptResult, error) {
+	return cached(b, "generate", req, func() (*PromptResult, error) {
+		return b.Backend.Generate(ctx, req)
+	})
+}
+
+// Embed implements Embedder.
+func (b *cachingBackend) Embed(
+	ctx context.Context,
+	model, input string,
+	options map[string]interface{},
+) (*EmbedResult, error) {
+	key := []interface{}{model, input, options}
+	return cached(b, "embed", key, func() (*EmbedResult, error) {
+		return b.Backend.Embed(ctx, model, input, options)
+	})
+}
+
+// EmbedBatch implements Embedder.
+func (b *cachingBackend) EmbedBatch(
+	ctx context.Context,
+	model string,
+	inputs []string,
+	opts *EmbedBatchOptions,
+) (*EmbedResult, error) {
+	key := []interface{}{model, inputs, opts}
+	return cached(b, "embed batch", key, func() (*EmbedResult, error) {
+		return b.Backend.EmbedBatch(ctx, model, inputs, opts)
+	})
+}
+
+// cached returns a deep copy of the result stored for op and key, or calls fn and stores a deep
+// copy of its result. Requests that cannot be encoded as a key and results that cannot be copied
+// bypass the cache.
+func cached[T any](
+	b *cachingBackend,
+	op string,
+	key interface{},
+	fn func() (*T, error),
+) (*T, error) {
+	data, err := json.Marshal(key)
+	if err != nil {
+		return fn()
+	}
+
+	cacheKey := op + "\x00" + string(data)
+
+	b.mu.Lock()
+	hit, ok := b.entries[cacheKey].(*T)
+	b.mu.Unlock()
+
+	if ok {
+		if out, err := cloneResult(hit); err == nil {
+			return out, nil
+		}
+	}
+
+	resp, err := fn()
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := cloneResult(resp)
+	if err != nil {
+		return resp, nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, exists := b.entries[cacheKey]; !exists {
+		if len(b.order) >= b.maxEntries {
+			delete(b.entries, b.order[0])
+			b.order = b.order[1:]
+		}
+
+		b.order = append(b.order, cacheKey)
+	}
+
+	b.entries[cacheKey] = stored
+	return resp, nil
+}
+
+// cloneResult returns a deep copy of v made by a JSON round trip, so that a cached result shares
+// no slices or maps with the values handed to callers.
+func cloneResult[T any](v *T) (*T, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	out := new(T)
+	if err := json.Unmarshal(data, out); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloom_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/nthnn/golloom"
+	"github.com/nthnn/golloom/golloomtest"
+)
+
+func TestCachingReturnsIndependentCopies(t *testing.T) {
+	srv := golloomtest.NewServer()
+	defer srv.Close()
+
+	backend := golloom.Chain(srv.Client(), golloom.Caching(8))
+	ctx := context.Background()
+	inputs := []string{"a", "b"}
+
+	r1, err := backend.EmbedBatch(ctx, "embedder", inputs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := r1.Embeddings[0][0]
+	r1.Embeddings[0][0] = 42
+
+	r2, err := backend.EmbedBatch(ctx, "embedder", inputs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := r2.Embeddings[0][0]; got != want {
+		t.Fatalf("cached value = %v after mutating the first result, want %v", got, want)
+	}
+
+	r2.Embeddings[0] = nil
+
+	r3, err := backend.EmbedBatch(ctx, "embedder", inputs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(r3.Embeddings[0]) == 0 || r3.Embeddings[0][0] != want {
+		t.Errorf("cached vector = %v after mutating a cache hit", r3.Embeddings[0])
+	}
+
+	if got := len(srv.RequestsTo("/api/embed")); got != 1 {
+		t.Errorf("embed requests = %d, want 1", got)
+	}
+}
+
+func TestCachingSkipsFailures(t *testing.T) {
+	srv := golloomtest.NewServer()
+	defer srv.Close()
+
+	srv.Enqueue("/api/chat", golloomtest.ErrorResponse(http.StatusBadRequest, "bad request"))
+
+	backend := golloom.Chain(srv.Client(), golloom.Caching(8))
+	ctx := context.Background()
+	stream := false
+	req := &golloom.Chat{
+		Model:    "llama3",
+		Messages: []golloom.Message{{Role: "user", Content: "hello"}},
+		Stream:   &stream,
+	}
+
+	if _, err := backend.Chat(ctx, req); err == nil {
+		t.Fatal("expected the scripted error")
+	}
+
+	for i := 0; i < 2; i++ {
+		resp, err := backend.Chat(ctx, req)
+		if err != nil || resp.Message.Content != "hello" {
+			t.Fatalf("reply %d = %+v, %v", i, resp, err)
+		}
+
+		resp.Message.Content = "changed"
+	}
+
+	if got := len(srv.RequestsTo("/api/chat")); got != 2 {
+		t.Errorf("chat requests = %d, want 2", got)
+	}
+}
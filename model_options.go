@@ -0,0 +1,414 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloom
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ModelOptions is a typed set of runtime parameters for a model, mirroring the "options" object
+// accepted by the chat, generate, and embed endpoints and the parameters of a created model.
+// Every field is optional; nil fields are omitted so the server's defaults apply. Use ToMap to
+// fill the map-based Options and Parameters fields, and ModelOptionsFromMap to read them back.
+type ModelOptions struct {
+	NumKeep          *int     `json:"num_keep,omitempty"`          // The number of tokens from the initial prompt to keep when the context is refreshed.
+	Seed             *int     `json:"seed,omitempty"`              // The random seed used for generation; a fixed seed makes output reproducible.
+	NumPredict       *int     `json:"num_predict,omitempty"`       // The maximum number of tokens to generate; -1 is unlimited and -2 fills the context.
+	TopK             *int     `json:"top_k,omitempty"`             // Limits sampling to the K most likely tokens.
+	TopP             *float64 `json:"top_p,omitempty"`             // Limits sampling to the smallest token set whose cumulative probability exceeds P.
+	MinP             *float64 `json:"min_p,omitempty"`             // The minimum probability of a token, relative to the most likely one, to be considered.
+	TypicalP         *float64 `json:"typical_p,omitempty"`         // The locally typical sampling threshold.
+	RepeatLastN      *int     `json:"repeat_last_n,omitempty"`     // How far back the model looks to prevent repetition; 0 disables it and -1 uses num_ctx.
+	Temperature      *float64 `json:"temperature,omitempty"`       // The sampling temperature; higher values make output more creative.
+	RepeatPenalty    *float64 `json:"repeat_penalty,omitempty"`    // How strongly repetitions are penalized.
+	PresencePenalty  *float64 `json:"presence_penalty,omitempty"`  // The penalty applied to tokens that already appeared in the text.
+	FrequencyPenalty *float64 `json:"frequency_penalty,omitempty"` // The penalty applied in proportion to how often a token appeared.
+	Mirostat         *int     `json:"mirostat,omitempty"`          // Enables Mirostat sampling: 0 disables it, 1 selects Mirostat and 2 Mirostat 2.0.
+	MirostatTau      *float64 `json:"mirostat_tau,omitempty"`      // The Mirostat target entropy, balancing coherence and diversity.
+	MirostatEta      *float64 `json:"mirostat_eta,omitempty"`      // The Mirostat learning rate.
+	PenalizeNewline  *bool    `json:"penalize_newline,omitempty"`  // Whether the newline token is subject to the repeat penalty.
+	Stop             []string `json:"stop,omitempty"`              // Sequences at which generation stops.
+	Numa             *bool    `json:"numa,omitempty"`              // Whether NUMA optimizations are enabled.
+	NumCtx           *int     `json:"num_ctx,omitempty"`           // The size of the context window in tokens.
+	NumBatch         *int     `json:"num_batch,omitempty"`         // The batch size used for prompt processing.
+	NumGPU           *int     `json:"num_gpu,omitempty"`           // The number of layers offloaded to the GPU.
+	MainGPU          *int     `json:"main_gpu,omitempty"`          // The GPU used for small tensors when splitting across several GPUs.
+	LowVRAM          *bool    `json:"low_vram,omitempty"`          // Whether to reduce VRAM usage at the cost of speed.
+	VocabOnly        *bool    `json:"vocab_only,omitempty"`        // Whether to load only the vocabulary, not the weights.
+	UseMMap          *bool    `json:"use_mmap,omitempty"`          // Whether the model is memory-mapped instead of read into memory.
+	UseMLock         *bool    `json:"use_mlock,omitempty"`         // Whether the model is locked in memory to prevent swapping.
+	NumThread        *int     `json:"num_thread,omitempty"`        // The number of CPU threads used for computation.
+}
+
+// NewModelOptions returns an empty ModelOptions, ready to be filled with its chainable setters.
+func NewModelOptions() *ModelOptions {
+	return &ModelOptions{}
+}
+
+// SetNumKeep sets the num_keep parameter and returns o for chaining.
+func (o *ModelOptions) SetNumKeep(v int) *ModelOptions {
+	o.NumKeep = &v
+	return o
+}
+
+// SetSeed sets the seed parameter and returns o for chaining.
+func (o *ModelOptions) SetSeed(v int) *ModelOptions {
+	o.Seed = &v
+	return o
+}
+
+// SetNumPredict sets the num_predict parameter and returns o for chaining.
+func (o *ModelOptions) SetNumPredict(v int) *ModelOptions {
+	o.NumPredict = &v
+	return o
+}
+
+// SetTopK sets the top_k parameter and returns o for chaining.
+func (o *ModelOptions) SetTopK(v int) *ModelOptions {
+	o.TopK = &v
+	return o
+}
+
+// SetTopP sets the top_p parameter and returns o for chaining.
+func (o *ModelOptions) SetTopP(v float64) *ModelOptions {
+	o.TopP = &v
+	return o
+}
+
+// SetMinP sets the min_p parameter and returns o for chaining.
+func (o *ModelOptions) SetMinP(v float64) *ModelOptions {
+	o.MinP = &v
+	return o
+}
+
+// SetTypicalP sets the typical_p parameter and returns o for chaining.
+func (o *ModelOptions) SetTypicalP(v float64) *ModelOptions {
+	o.TypicalP = &v
+	return o
+}
+
+// SetRepeatLastN sets the repeat_last_n parameter and returns o for chaining.
+func (o *ModelOptions) SetRepeatLastN(v int) *ModelOptions {
+	o.RepeatLastN = &v
+	return o
+}
+
+// SetTemperature sets the temperature parameter and returns o for chaining.
+func (o *ModelOptions) SetTemperature(v float64) *ModelOptions {
+	o.Temperature = &v
+	return o
+}
+
+// SetRepeatPenalty sets the repeat_penalty parameter and returns o for chaining.
+func (o *ModelOptions) SetRepeatPenalty(v float64) *ModelOptions {
+	o.RepeatPenalty = &v
+	return o
+}
+
+// SetPresencePenalty sets the presence_penalty parameter and returns o for chaining.
+func (o *ModelOptions) SetPresencePenalty(v float64) *ModelOptions {
+	o.PresencePenalty = &v
+	return o
+}
+
+// SetFrequencyPenalty sets the frequency_penalty parameter and returns o for chaining.
+func (o *ModelOptions) SetFrequencyPenalty(v float64) *ModelOptions {
+	o.FrequencyPenalty = &v
+	return o
+}
+
+// SetMirostat sets the mirostat parameter and returns o for chaining.
+func (o *ModelOptions) SetMirostat(v int) *ModelOptions {
+	o.Mirostat = &v
+	return o
+}
+
+// SetMirostatTau sets the mirostat_tau parameter and returns o for chaining.
+func (o *ModelOptions) SetMirostatTau(v float64) *ModelOptions {
+	o.MirostatTau = &v
+	return o
+}
+
+// SetMirostatEta sets the mirostat_eta parameter and returns o for chaining.
+func (o *ModelOptions) SetMirostatEta(v float64) *ModelOptions {
+	o.MirostatEta = &v
+	return o
+}
+
+// SetPenalizeNewline sets the penalize_newline parameter and returns o for chaining.
+func (o *ModelOptions) SetPenalizeNewline(v bool) *ModelOptions {
+	o.PenalizeNewline = &v
+	return o
+}
+
+// SetStop sets the stop parameter and returns o for chaining.
+func (o *ModelOptions) SetStop(v ...string) *ModelOptions {
+	o.Stop = append([]string(nil), v...)
+	return o
+}
+
+// SetNuma sets the numa parameter and returns o for chaining.
+func (o *ModelOptions) SetNuma(v bool) *ModelOptions {
+	o.Numa = &v
+	return o
+}
+
+// SetNumCtx sets the num_ctx parameter and returns o for chaining.
+func (o *ModelOptions) SetNumCtx(v int) *ModelOptions {
+	o.NumCtx = &v
+	return o
+}
+
+// SetNumBatch sets the num_batch parameter and returns o for chaining.
+func (o *ModelOptions) SetNumBatch(v int) *ModelOptions {
+	o.NumBatch = &v
+	return o
+}
+
+// SetNumGPU sets the num_gpu parameter and returns o for chaining.
+func (o *ModelOptions) SetNumGPU(v int) *ModelOptions {
+	o.NumGPU = &v
+	return o
+}
+
+// SetMainGPU sets the main_gpu parameter and returns o for chaining.
+func (o *ModelOptions) SetMainGPU(v int) *ModelOptions {
+	o.MainGPU = &v
+	return o
+}
+
+// SetLowVRAM sets the low_vram parameter and returns o for chaining.
+func (o *ModelOptions) SetLowVRAM(v bool) *ModelOptions {
+	o.LowVRAM = &v
+	return o
+}
+
+// SetVocabOnly sets the vocab_only parameter and returns o for chaining.
+func (o *ModelOptions) SetVocabOnly(v bool) *ModelOptions {
+	o.VocabOnly = &v
+	return o
+}
+
+// SetUseMMap sets the use_mmap parameter and returns o for chaining.
+func (o *ModelOptions) SetUseMMap(v bool) *ModelOptions {
+	o.UseMMap = &v
+	return o
+}
+
+// SetUseMLock sets the use_mlock parameter and returns o for chaining.
+func (o *ModelOptions) SetUseMLock(v bool) *ModelOptions {
+	o.UseMLock = &v
+	return o
+}
+
+// SetNumThread sets the num_thread parameter and returns o for chaining.
+func (o *ModelOptions) SetNumThread(v int) *ModelOptions {
+	o.NumThread = &v
+	return o
+}
+
+// Validate checks that every set parameter lies within its documented range.
+// All violations are reported together in a single joined error.
+func (o *ModelOptions) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	checkUnit := func(name string, v *float64) {
+		if v != nil {
+			check(*v >= 0 && *v <= 1, "%s must be between 0 and 1, got %v", name, *v)
+		}
+	}
+
+	checkNonNegative := func(name string, v *int) {
+		if v != nil {
+			check(*v >= 0, "%s must not be negative, got %d", name, *v)
+		}
+	}
+
+	checkUnit("top_p", o.TopP)
+	checkUnit("min_p", o.MinP)
+	checkUnit("typical_p", o.TypicalP)
+
+	checkNonNegative("num_keep", o.NumKeep)
+	checkNonNegative("top_k", o.TopK)
+	checkNonNegative("num_batch", o.NumBatch)
+	checkNonNegative("main_gpu", o.MainGPU)
+	checkNonNegative("num_thread", o.NumThread)
+
+	if o.Temperature != nil {
+		check(*o.Temperature >= 0, "temperature must not be negative, got %v", *o.Temperature)
+	}
+
+	if o.RepeatPenalty != nil {
+		check(*o.RepeatPenalty >= 0, "repeat_penalty must not be negative, got %v", *o.RepeatPenalty)
+	}
+
+	if o.NumPredict != nil {
+		check(*o.NumPredict >= -2, "num_predict must be -2, -1, or a non-negative count, got %d", *o.NumPredict)
+	}
+
+	if o.RepeatLastN != nil {
+		check(*o.RepeatLastN >= -1, "repeat_last_n must be -1 or a non-negative count, got %d", *o.RepeatLastN)
+	}
+
+	if o.Mirostat != nil {
+		check(*o.Mirostat >= 0 && *o.Mirostat <= 2, "mirostat must be 0, 1, or 2, got %d", *o.Mirostat)
+	}
+
+	if o.MirostatTau != nil {
+		check(*o.MirostatTau >= 0, "mirostat_tau must not be negative, got %v", *o.MirostatTau)
+	}
+
+	if o.MirostatEta != nil {
+		check(*o.MirostatEta >= 0, "mirostat_eta must not be negative, got %v", *o.MirostatEta)
+	}
+
+	if o.NumCtx != nil {
+		check(*o.NumCtx > 0, "num_ctx must be positive, got %d", *o.NumCtx)
+	}
+
+	if o.NumGPU != nil {
+		check(*o.NumGPU >= -1, "num_gpu must be -1 or a non-negative count, got %d", *o.NumGPU)
+	}
+
+	return errors.Join(errs...)
+}
+
+// ToMap converts the options into the map form used by Chat.Options, PromptInfo.Options,
+// CreateModelRequest.Parameters, and the options argument of Embed. Only set parameters are
+// included, and each value keeps its Go type (int, float64, bool, or []string).
+func (o *ModelOptions) ToMap() map[string]interface{} {
+	m := make(map[string]interface{})
+	if o == nil {
+		return m
+	}
+
+	v := reflect.ValueOf(o).Elem()
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := v.Field(i)
+		if field.IsNil() {
+			continue
+		}
+
+		name := modelOptionName(t.Field(i))
+		if field.Kind() == reflect.Pointer {
+			m[name] = field.Elem().Interface()
+		} else {
+			m[name] = field.Interface()
+		}
+	}
+
+	return m
+}
+
+// ModelOptionsFromMap converts a map-based options value back into a ModelOptions.
+// Unknown keys, such as a misspelled "temprature", and values of the wrong type are
+// reported as errors instead of being silently ignored.
+// Parameters:
+//   - m: The options map, e.g. Chat.Options or CreateModelRequest.Parameters.
+//
+// Returns:
+//   - A pointer to the decoded ModelOptions.
+//   - An error if the map contains unknown keys or values of the wrong type.
+func ModelOptionsFromMap(m map[string]interface{}) (*ModelOptions, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &ModelOptions{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(opts); err != nil {
+		return nil, fmt.Errorf("invalid model options: %w", err)
+	}
+
+	return opts, nil
+}
+
+// SetParameter sets the parameter called name from its textual value, as written in a
+// Modelfile PARAMETER line or in the parameters reported by FetchModelInfo. The value is
+// parsed according to the type of the parameter; values for "stop" are appended, so a
+// repeated stop parameter accumulates every sequence.
+// Parameters:
+//   - name: The parameter name, e.g. "num_ctx" or "temperature".
+//   - value: The textual value, e.g. "4096" or "0.7"; surrounding quotes are not removed.
+//
+// Returns:
+//   - An error if the name is unknown or the value cannot be parsed as the parameter's type.
+func (o *ModelOptions) SetParameter(name, value string) error {
+	v := reflect.ValueOf(o).Elem()
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		if modelOptionName(t.Field(i)) != name {
+			continue
+		}
+
+		field := v.Field(i)
+		if field.Kind() == reflect.Slice {
+			field.Set(reflect.Append(field, reflect.ValueOf(value)))
+			return nil
+		}
+
+		var parsed interface{}
+		var err error
+
+		switch field.Type().Elem().Kind() {
+		case reflect.Int:
+			parsed, err = strconv.Atoi(value)
+
+		case reflect.Float64:
+			parsed, err = strconv.ParseFloat(value, 64)
+
+		case reflect.Bool:
+			parsed, err = strconv.ParseBool(value)
+		}
+
+		if err != nil {
+			return fmt.Errorf("invalid value %q for parameter %s", value, name)
+		}
+
+		ptr := reflect.New(field.Type().Elem())
+		ptr.Elem().Set(reflect.ValueOf(parsed))
+		field.Set(ptr)
+
+		return nil
+	}
+
+	return fmt.Errorf("unknown parameter %q", name)
+}
+
+// modelOptionName returns the parameter name of a ModelOptions field from its json tag.
+func modelOptionName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	return name
+}
@@ -0,0 +1,246 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloom
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The defaults Ollama applies to the parts omitted from a model name.
+const (
+	DefaultModelHost      = "registry.ollama.ai"
+	DefaultModelNamespace = "library"
+	DefaultModelTag       = "latest"
+)
+
+// ModelRef is a parsed model name of the form [host/][namespace/]name[:tag][@digest], as accepted
+// by PullModel, PushModel, CopyModel, DeleteModel, and Chat.Model. Names that differ only in their
+// omitted defaults, such as "llama3", "library/llama3:latest" and
+// "registry.ollama.ai/library/llama3:latest", parse to equal references.
+type ModelRef struct {
+	Host      string // The registry host, optionally with a port, e.g. "registry.ollama.ai".
+	Namespace string // The namespace, usually a user or organization, e.g. "library".
+	Name      string // The model name, e.g. "llama3".
+	Tag       string // The tag, e.g. "latest" or "8b-instruct-q4_0".
+	Digest    string // An optional "sha256:" digest pinning the exact model; empty when not pinned.
+}
+
+// ParseModelRef parses a model name, filling in the default host, namespace, and tag for the
+// parts it omits. A tag is recognized only after the last "/", so a host may carry a port.
+// Parameters:
+//   - name: The model name, e.g. "llama3", "myuser/mymodel:v2", or "localhost:5000/ns/model@sha256:...".
+//
+// Returns:
+//   - The parsed ModelRef.
+//   - An error if the name has too many parts or a part is empty, too long, or contains invalid characters.
+func ParseModelRef(name string) (ModelRef, error) {
+	ref := ModelRef{
+		Host:      DefaultModelHost,
+		Namespace: DefaultModelNamespace,
+		Tag:       DefaultModelTag,
+	}
+
+	rest := name
+	if i := strings.LastIndex(rest, "@"); i >= 0 {
+		ref.Digest = strings.ToLower(rest[i+1:])
+		rest = rest[:i]
+	}
+
+	if i := strings.LastIndex(rest, ":"); i > strings.LastIndex(rest, "/") {
+		ref.Tag = rest[i+1:]
+		rest = rest[:i]
+	}
+
+	parts := strings.Split(rest, "/")
+	switch len(parts) {
+	case 1:
+		ref.Name = parts[0]
+
+	case 2:
+		ref.Namespace, ref.Name = parts[0], parts[1]
+
+	case 3:
+		ref.Host, ref.Namespace, ref.Name = parts[0], parts[1], parts[2]
+
+	default:
+		return ModelRef{}, fmt.Errorf("invalid model name %q: too many parts", name)
+	}
+
+	if err := ref.Validate(); err != nil {
+		return ModelRef{}, fmt.Errorf("invalid model name %q: %w", name, err)
+	}
+
+	return ref, nil
+}
+
+// Validate checks every part of the reference against Ollama's naming rules: parts start with a
+// letter, digit, or underscore and continue with those characters, "-" and, except in the
+// namespace, "."; the host may also contain ":" for a port. The digest, if set, must be
+// "sha256:" followed by 64 hexadecimal digits.
+func (r ModelRef) Validate() error {
+	checks := []struct {
+		kind, value, extra string
+		max                int
+	}{
+		{"host", r.Host, ".:", 350},
+		{"namespace", r.Namespace, "", 80},
+		{"name", r.Name, ".", 80},
+		{"tag", r.Tag, ".", 80},
+	}
+
+	for _, check := range checks {
+		if err := validateRefPart(check.value, check.extra, check.max); err != nil {
+			return fmt.Errorf("%s %q %w", check.kind, check.value, err)
+		}
+	}
+
+	if r.Digest != "" {
+		hexDigits, ok := strings.CutPrefix(r.Digest, "sha256:")
+		if !ok || len(hexDigits) != 64 || strings.Trim(hexDigits, "0123456789abcdef") != "" {
+			return fmt.Errorf("digest %q is not a sha256 digest", r.Digest)
+		}
+	}
+
+	return nil
+}
+
+// validateRefPart checks a single part of a model name, allowing the punctuation in extra
+// besides "_" and "-" after its first character.
+func validateRefPart(
+	part, extra string,
+	max int,
+) error {
+	if part == "" {
+		return fmt.Errorf("is empty")
+	}
+
+	if len(part) > max {
+		return fmt.Errorf("is longer than %d characters", max)
+	}
+
+	for i, c := range part {
+		alnum := c == '_' ||
+			(c >= 'a' && c <= 'z') ||
+			(c >= 'A' && c <= 'Z') ||
+			(c >= '0' && c <= '9')
+
+		if alnum || (i > 0 && (c == '-' || strings.ContainsRune(extra, c))) {
+			continue
+		}
+
+		return fmt.Errorf("contains invalid character %q", c)
+	}
+
+	return nil
+}
+
+// String returns the shortest form of the reference, the one ListModels reports: the default
+// host and namespace are omitted while the tag is always kept, e.g. "llama3:latest" or
+// "myuser/mymodel:v2", followed by "@" and the digest when pinned.
+func (r ModelRef) String() string {
+	var sb strings.Builder
+	if !strings.EqualFold(r.Host, DefaultModelHost) {
+		sb.WriteString(r.Host + "/" + r.Namespace + "/")
+	} else if !strings.EqualFold(r.Namespace, DefaultModelNamespace) {
+		sb.WriteString(r.Namespace + "/")
+	}
+
+	sb.WriteString(r.Name + ":" + r.Tag)
+	if r.Digest != "" {
+		sb.WriteString("@" + r.Digest)
+	}
+
+	return sb.String()
+}
+
+// Full returns the fully qualified form of the reference, e.g. "registry.ollama.ai/library/llama3:latest",
+// followed by "@" and the digest when pinned.
+func (r ModelRef) Full() string {
+	full := r.Host + "/" + r.Namespace + "/" + r.Name + ":" + r.Tag
+	if r.Digest != "" {
+		full += "@" + r.Digest
+	}
+
+	return full
+}
+
+// Equal reports whether r and other refer to the same model. Like Ollama, the comparison
+// ignores case; digests must match as well.
+func (r ModelRef) Equal(other ModelRef) bool {
+	return strings.EqualFold(r.Full(), other.Full())
+}
+
+// Matches reports whether the listed model m is the one r refers to. When r is pinned to a
+// digest, the digest of m must match it too.
+func (r ModelRef) Matches(m *ModelInfo) bool {
+	ref, err := ParseModelRef(m.Name)
+	if err != nil {
+		return false
+	}
+
+	ref.Digest = r.Digest
+	if !r.Equal(ref) {
+		return false
+	}
+
+	return r.Digest == "" ||
+		strings.EqualFold(strings.TrimPrefix(r.Digest, "sha256:"), strings.TrimPrefix(m.Digest, "sha256:"))
+}
+
+// MarshalText implements encoding.TextMarshaler using the short form returned by String.
+func (r ModelRef) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using ParseModelRef.
+func (r *ModelRef) UnmarshalText(text []byte) error {
+	ref, err := ParseModelRef(string(text))
+	if err != nil {
+		return err
+	}
+
+	*r = ref
+	return nil
+}
+
+// Find returns the listed model that ref refers to. The second result reports whether one was found.
+func (l *ModelList) Find(ref ModelRef) (*ModelInfo, bool) {
+	for i := range l.Models {
+		if ref.Matches(&l.Models[i]) {
+			return &l.Models[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// normalizeModelName returns the canonical short form of a model name for comparisons, falling
+// back to the name with a default tag when it cannot be parsed.
+func normalizeModelName(name string) string {
+	ref, err := ParseModelRef(name)
+	if err != nil {
+		if i := strings.LastIndex(name, ":"); i <= strings.LastIndex(name, "/") {
+			name += ":" + DefaultModelTag
+		}
+
+		return strings.ToLower(name)
+	}
+
+	return strings.ToLower(ref.String())
+}
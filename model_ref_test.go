@@ -0,0 +1,232 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloom
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const testDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func TestParseModelRef(t *testing.T) {
+	tests := []struct {
+		name  string
+		want  ModelRef
+		short string
+	}{
+		{
+			"llama3",
+			ModelRef{Host: DefaultModelHost, Namespace: DefaultModelNamespace, Name: "llama3", Tag: DefaultModelTag},
+			"llama3:latest",
+		},
+		{
+			"llama3:8b-instruct-q4_0",
+			ModelRef{Host: DefaultModelHost, Namespace: DefaultModelNamespace, Name: "llama3", Tag: "8b-instruct-q4_0"},
+			"llama3:8b-instruct-q4_0",
+		},
+		{
+			"myuser/my-model:v2.1",
+			ModelRef{Host: DefaultModelHost, Namespace: "myuser", Name: "my-model", Tag: "v2.1"},
+			"myuser/my-model:v2.1",
+		},
+		{
+			"localhost:5000/ns/model",
+			ModelRef{Host: "localhost:5000", Namespace: "ns", Name: "model", Tag: DefaultModelTag},
+			"localhost:5000/ns/model:latest",
+		},
+		{
+			"qwen2.5@" + strings.ToUpper(testDigest),
+			ModelRef{Host: DefaultModelHost, Namespace: DefaultModelNamespace, Name: "qwen2.5", Tag: DefaultModelTag, Digest: testDigest},
+			"qwen2.5:latest@" + testDigest,
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseModelRef(tt.name)
+		if err != nil {
+			t.Errorf("ParseModelRef(%q): %v", tt.name, err)
+			continue
+		}
+
+		if got != tt.want {
+			t.Errorf("ParseModelRef(%q) = %+v, want %+v", tt.name, got, tt.want)
+		}
+
+		if s := got.String(); s != tt.short {
+			t.Errorf("ParseModelRef(%q).String() = %q, want %q", tt.name, s, tt.short)
+		}
+	}
+}
+
+func TestParseModelRefErrors(t *testing.T) {
+	names := []string{
+		"",
+		"a/b/c/d",
+		"llama3:",
+		"/llama3",
+		"-model",
+		"my.ns/model",
+		"model name",
+		"model:tag!",
+		"model@sha256:1234",
+		"model@md5:" + strings.Repeat("0", 64),
+		strings.Repeat("m", 81),
+	}
+
+	for _, name := range names {
+		if ref, err := ParseModelRef(name); err == nil {
+			t.Errorf("ParseModelRef(%q) = %+v, want an error", name, ref)
+		}
+	}
+}
+
+func TestModelRefFull(t *testing.T) {
+	ref, err := ParseModelRef("llama3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := ref.Full(); got != "registry.ollama.ai/library/llama3:latest" {
+		t.Errorf("Full() = %q", got)
+	}
+}
+
+func TestModelRefEqual(t *testing.T) {
+	equal := [][2]string{
+		{"llama3", "llama3:latest"},
+		{"llama3", "library/llama3"},
+		{"llama3", "registry.ollama.ai/library/llama3:latest"},
+		{"Llama3:Latest", "llama3"},
+	}
+
+	for _, pair := range equal {
+		a, _ := ParseModelRef(pair[0])
+		b, _ := ParseModelRef(pair[1])
+
+		if !a.Equal(b) {
+			t.Errorf("%q and %q are not equal", pair[0], pair[1])
+		}
+	}
+
+	different := [][2]string{
+		{"llama3", "llama3:8b"},
+		{"llama3", "myuser/llama3"},
+		{"llama3", "llama3@" + testDigest},
+	}
+
+	for _, pair := range different {
+		a, _ := ParseModelRef(pair[0])
+		b, _ := ParseModelRef(pair[1])
+
+		if a.Equal(b) {
+			t.Errorf("%q and %q are equal", pair[0], pair[1])
+		}
+	}
+}
+
+func TestModelListFind(t *testing.T) {
+	list := &ModelList{Models: []ModelInfo{
+		{Name: "mistral:latest", Digest: "ffff"},
+		{Name: "llama3:latest", Digest: strings.TrimPrefix(testDigest, "sha256:")},
+		{Name: "myuser/llama3:v2"},
+	}}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"llama3", "llama3:latest"},
+		{"registry.ollama.ai/library/llama3", "llama3:latest"},
+		{"llama3@" + testDigest, "llama3:latest"},
+		{"myuser/llama3:v2", "myuser/llama3:v2"},
+		{"mistral@" + testDigest, ""},
+		{"llama3:8b", ""},
+	}
+
+	for _, tt := range tests {
+		ref, err := ParseModelRef(tt.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m, ok := list.Find(ref)
+		if tt.want == "" {
+			if ok {
+				t.Errorf("Find(%q) = %q, want no match", tt.name, m.Name)
+			}
+
+			continue
+		}
+
+		if !ok || m.Name != tt.want {
+			t.Errorf("Find(%q) = %v, %v; want %q", tt.name, m, ok, tt.want)
+		}
+	}
+}
+
+func TestModelRefText(t *testing.T) {
+	var v struct {
+		Model ModelRef `json:"model"`
+	}
+
+	if err := json.Unmarshal([]byte(`{"model":"registry.ollama.ai/myuser/coder:7b"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.Model.Namespace != "myuser" || v.Model.Tag != "7b" {
+		t.Errorf("decoded %+v", v.Model)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != `{"model":"myuser/coder:7b"}` {
+		t.Errorf("encoded %s", data)
+	}
+
+	if err := json.Unmarshal([]byte(`{"model":"bad name"}`), &v); err == nil {
+		t.Error("an invalid name was decoded")
+	}
+}
+
+func TestNormalizeModelName(t *testing.T) {
+	status := &ModelProcessStatus{Models: []RunningModel{
+		{Name: "registry.ollama.ai/library/Llama3:latest"},
+		{Name: "myuser/coder:7b"},
+	}}
+
+	for _, name := range []string{"llama3", "library/llama3:latest", "myuser/coder:7b"} {
+		if !status.IsLoaded(name) {
+			t.Errorf("IsLoaded(%q) = false", name)
+		}
+	}
+
+	for _, name := range []string{"llama3:8b", "coder:7b"} {
+		if status.IsLoaded(name) {
+			t.Errorf("IsLoaded(%q) = true", name)
+		}
+	}
+
+	if got := normalizeModelName("Bad Name"); got != "bad name:latest" {
+		t.Errorf("normalizeModelName(%q) = %q", "Bad Name", got)
+	}
+}
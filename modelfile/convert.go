@@ -0,0 +1,305 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package modelfile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nthnn/golloom"
+)
+
+// ErrLocalPath is returned by CreateModelRequest when a FROM or ADAPTER directive refers to
+// local files, which have to be uploaded first; use Modelfile.Create for such Modelfiles.
+var ErrLocalPath = errors.New("modelfile refers to local files")
+
+// CreateModelRequest converts the Modelfile into a request creating model from a base model.
+// A relative FROM value is taken as local when it exists relative to the working directory.
+// PARAMETER values are parsed into their types with golloom.ModelOptions.SetParameter, so
+// unknown parameters and malformed values are reported here rather than by the server.
+// Parameters:
+//   - model: The name of the model to create.
+//
+// Returns:
+//   - A pointer to the CreateModelRequest.
+//   - An error wrapping ErrLocalPath if FROM or ADAPTER refers to local files, or an error
+//     if FROM is missing or a parameter is invalid.
+func (m *Modelfile) CreateModelRequest(model string) (*golloom.CreateModelRequest, error) {
+	req, weights, adapters, err := m.createRequest(model, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	if weights != "" {
+		return nil, fmt.Errorf("%w: FROM %s", ErrLocalPath, weights)
+	}
+
+	if len(adapters) > 0 {
+		return nil, fmt.Errorf("%w: ADAPTER %s", ErrLocalPath, adapters[0])
+	}
+
+	return req, nil
+}
+
+// Create creates model on the server from the Modelfile. Local weights named by FROM and the
+// adapter named by ADAPTER are resolved against dir, where a FROM value without a path prefix
+// or weights extension is local only if it exists, uploaded with CreateModelFromPath, and
+// referenced by digest; otherwise the request is sent as built by CreateModelRequest.
+// Parameters:
+//   - ctx: A context.Context object for managing request deadlines and cancellations.
+//   - c: The client used to upload files and create the model.
+//   - model: The name of the model to create.
+//   - dir: The directory relative paths are resolved against, usually the Modelfile's own.
+//   - fn: An optional callback receiving the upload and creation events.
+//
+// Returns:
+//   - A pointer to a CreateModelResult struct containing status messages from the creation.
+//   - An error if the Modelfile is invalid, holds more than one ADAPTER, or the creation fails.
+func (m *Modelfile) Create(
+	ctx context.Context,
+	c *golloom.Client,
+	model, dir string,
+	fn golloom.ProgressFunc,
+) (*golloom.CreateModelResult, error) {
+	req, weights, adapters, err := m.createRequest(model, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(adapters) > 1 {
+		return nil, fmt.Errorf("only one ADAPTER is supported, found %d", len(adapters))
+	}
+
+	if weights == "" && len(adapters) == 0 {
+		return c.CreateModelProgress(ctx, req, fn)
+	}
+
+	var adapter string
+	if len(adapters) == 1 {
+		if adapter, err = resolvePath(dir, adapters[0]); err != nil {
+			return nil, err
+		}
+	}
+
+	if weights != "" {
+		if weights, err = resolvePath(dir, weights); err != nil {
+			return nil, err
+		}
+	}
+
+	return c.CreateModelFromPath(ctx, req, weights, adapter, fn)
+}
+
+// createRequest builds the request for model and returns the local weights path named by FROM,
+// if any, and the adapter paths, which are always local, separately. Relative paths are checked
+// against dir.
+func (m *Modelfile) createRequest(model, dir string) (
+	*golloom.CreateModelRequest,
+	string,
+	[]string,
+	error,
+) {
+	from := m.Get(From)
+	if from == "" {
+		return nil, "", nil, fmt.Errorf("modelfile has no FROM directive")
+	}
+
+	req := &golloom.CreateModelRequest{
+		Model:    model,
+		Template: m.Get(Template),
+		System:   m.Get(System),
+	}
+
+	var weights string
+	if isLocalPath(dir, from) {
+		weights = from
+	} else {
+		req.From = from
+	}
+
+	if params := m.All(Parameter); len(params) > 0 {
+		opts := golloom.NewModelOptions()
+		for _, d := range params {
+			if err := opts.SetParameter(d.Name, d.Value); err != nil {
+				return nil, "", nil, err
+			}
+		}
+
+		req.Parameters = opts.ToMap()
+	}
+
+	for _, d := range m.All(Message) {
+		req.Messages = append(req.Messages, golloom.Message{
+			Role:    d.Name,
+			Content: d.Value,
+		})
+	}
+
+	switch licenses := m.All(License); len(licenses) {
+	case 0:
+
+	case 1:
+		req.License = licenses[0].Value
+
+	default:
+		texts := make([]string, len(licenses))
+		for i, d := range licenses {
+			texts[i] = d.Value
+		}
+
+		req.License = texts
+	}
+
+	var adapters []string
+	for _, d := range m.All(Adapter) {
+		adapters = append(adapters, d.Value)
+	}
+
+	return req, weights, adapters, nil
+}
+
+// FromCreateModelRequest builds the Modelfile equivalent to req. The model name, quantization,
+// and streaming settings are not part of a Modelfile and are ignored, as are message images and
+// tool calls. Uploaded Files and Adapters are known only by digest and cannot be expressed.
+// Parameters:
+//   - req: The request to convert.
+//
+// Returns:
+//   - A pointer to the Modelfile.
+//   - An error if req holds Files or Adapters, invalid parameters, or a license of an unexpected type.
+func FromCreateModelRequest(req *golloom.CreateModelRequest) (*Modelfile, error) {
+	if len(req.Files) > 0 || len(req.Adapters) > 0 {
+		return nil, fmt.Errorf("uploaded files and adapters cannot be expressed in a Modelfile")
+	}
+
+	mf := &Modelfile{}
+	if req.From != "" {
+		mf.Add(From, "", req.From)
+	}
+
+	if req.Template != "" {
+		mf.Add(Template, "", req.Template)
+	}
+
+	if req.System != "" {
+		mf.Add(System, "", req.System)
+	}
+
+	if len(req.Parameters) > 0 {
+		opts, err := golloom.ModelOptionsFromMap(req.Parameters)
+		if err != nil {
+			return nil, err
+		}
+
+		params := opts.ToMap()
+		names := make([]string, 0, len(params))
+
+		for name := range params {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if values, ok := params[name].([]string); ok {
+				for _, v := range values {
+					mf.Add(Parameter, name, v)
+				}
+
+				continue
+			}
+
+			mf.Add(Parameter, name, fmt.Sprint(params[name]))
+		}
+	}
+
+	for _, msg := range req.Messages {
+		mf.Add(Message, msg.Role, msg.Content)
+	}
+
+	switch license := req.License.(type) {
+	case nil:
+
+	case string:
+		mf.Add(License, "", license)
+
+	case []string:
+		for _, text := range license {
+			mf.Add(License, "", text)
+		}
+
+	case []interface{}:
+		for _, text := range license {
+			s, ok := text.(string)
+			if !ok {
+				return nil, fmt.Errorf("unexpected license of type %T", text)
+			}
+
+			mf.Add(License, "", s)
+		}
+
+	default:
+		return nil, fmt.Errorf("unexpected license of type %T", license)
+	}
+
+	return mf, nil
+}
+
+// isLocalPath reports whether a FROM value names local files rather than a model: it has a path
+// prefix or a weights extension, or it exists relative to dir, as a bare directory name may.
+func isLocalPath(dir, value string) bool {
+	for _, prefix := range []string{"/", "./", "../", "~/", `.\`, `..\`} {
+		if strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+
+	switch strings.ToLower(filepath.Ext(value)) {
+	case ".gguf", ".safetensors":
+		return true
+	}
+
+	if value == "." || value == ".." || filepath.IsAbs(value) {
+		return true
+	}
+
+	_, err := os.Stat(filepath.Join(dir, value))
+	return err == nil
+}
+
+// resolvePath expands a leading "~/" and makes a relative path relative to dir.
+func resolvePath(dir, path string) (string, error) {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+
+		return filepath.Join(home, rest), nil
+	}
+
+	if filepath.IsAbs(path) {
+		return path, nil
+	}
+
+	return filepath.Join(dir, path), nil
+}
@@ -0,0 +1,245 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package modelfile
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nthnn/golloom"
+	"github.com/nthnn/golloom/golloomtest"
+)
+
+func TestCreateModelRequest(t *testing.T) {
+	mf, err := Parse(strings.NewReader(sampleModelfile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := mf.CreateModelRequest("my-model")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if req.Model != "my-model" || req.From != "llama3" {
+		t.Errorf("model = %q, from = %q", req.Model, req.From)
+	}
+
+	if req.Template != "{{ .System }}\n{{ .Prompt }}" || req.System != `You are "helpful".` {
+		t.Errorf("template = %q, system = %q", req.Template, req.System)
+	}
+
+	wantParams := map[string]interface{}{
+		"temperature": 0.7,
+		"stop":        []string{"<|eot_id|>", "  "},
+	}
+
+	if !reflect.DeepEqual(req.Parameters, wantParams) {
+		t.Errorf("parameters = %#v, want %#v", req.Parameters, wantParams)
+	}
+
+	wantMessages := []golloom.Message{
+		{Role: "user", Content: "Hello"},
+		{Role: "assistant", Content: "Hi there"},
+	}
+
+	if !reflect.DeepEqual(req.Messages, wantMessages) {
+		t.Errorf("messages = %+v", req.Messages)
+	}
+
+	if req.License != "\nMIT\n" {
+		t.Errorf("license = %#v", req.License)
+	}
+}
+
+func TestCreateModelRequestErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		local bool
+	}{
+		{"no FROM", "SYSTEM hi", false},
+		{"unknown parameter", "FROM llama3\nPARAMETER warmth 1", false},
+		{"invalid value", "FROM llama3\nPARAMETER num_ctx large", false},
+		{"local weights", "FROM ./model.gguf", true},
+		{"weights extension", "FROM model.safetensors", true},
+		{"adapter", "FROM llama3\nADAPTER lora.gguf", true},
+	}
+
+	for _, tt := range tests {
+		mf, err := Parse(strings.NewReader(tt.text))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		_, err = mf.CreateModelRequest("m")
+		if err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+			continue
+		}
+
+		if got := errors.Is(err, ErrLocalPath); got != tt.local {
+			t.Errorf("%s: errors.Is(err, ErrLocalPath) = %v, want %v (err = %v)", tt.name, got, tt.local, err)
+		}
+	}
+}
+
+func TestIsLocalPath(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "mydir"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{"llama3", false},
+		{"llama3:8b", false},
+		{"user/model:latest", false},
+		{"mydir", true},
+		{"./weights", true},
+		{"../weights", true},
+		{"~/models/x", true},
+		{"/abs/path", true},
+		{".", true},
+		{"model.GGUF", true},
+		{"model.safetensors", true},
+	}
+
+	for _, tt := range tests {
+		if got := isLocalPath(dir, tt.value); got != tt.want {
+			t.Errorf("isLocalPath(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestFromCreateModelRequest(t *testing.T) {
+	req := &golloom.CreateModelRequest{
+		Model:    "ignored",
+		From:     "llama3",
+		System:   "Be brief.",
+		Template: "{{ .Prompt }}",
+		Parameters: map[string]interface{}{
+			"stop":        []interface{}{"a", "b"},
+			"num_ctx":     float64(4096),
+			"temperature": 0.5,
+		},
+		Messages: []golloom.Message{{Role: "user", Content: "Hi"}},
+		License:  []interface{}{"MIT", "Apache-2.0"},
+	}
+
+	mf, err := FromCreateModelRequest(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "FROM llama3\n" +
+		"TEMPLATE {{ .Prompt }}\n" +
+		"SYSTEM Be brief.\n" +
+		"PARAMETER num_ctx 4096\n" +
+		"PARAMETER stop a\n" +
+		"PARAMETER stop b\n" +
+		"PARAMETER temperature 0.5\n" +
+		"MESSAGE user Hi\n" +
+		"LICENSE MIT\n" +
+		"LICENSE Apache-2.0\n"
+
+	if got := mf.String(); got != want {
+		t.Errorf("String() =\n%s\nwant\n%s", got, want)
+	}
+
+	back, err := mf.CreateModelRequest("ignored")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if back.Parameters["num_ctx"] != 4096 || !reflect.DeepEqual(back.License, []string{"MIT", "Apache-2.0"}) {
+		t.Errorf("converted back to parameters %v, license %v", back.Parameters, back.License)
+	}
+
+	if _, err := FromCreateModelRequest(&golloom.CreateModelRequest{
+		Files: map[string]string{"model.gguf": "sha256:00"},
+	}); err == nil {
+		t.Error("a request with uploaded files was converted")
+	}
+}
+
+func TestCreateFromLocalDirectory(t *testing.T) {
+	dir := t.TempDir()
+	weights := []byte("GGUF weights")
+
+	if err := os.Mkdir(filepath.Join(dir, "mydir"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "mydir", "model.gguf"), weights, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := golloomtest.NewServer()
+	defer srv.Close()
+
+	srv.Enqueue("/api/create", golloomtest.ProgressResponse("creating model", "success"))
+
+	mf, err := Parse(strings.NewReader("FROM mydir\nPARAMETER num_ctx 2048\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := mf.Create(context.Background(), srv.Client(), "local-model", dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res.StatusMessages) == 0 || res.StatusMessages[len(res.StatusMessages)-1] != "success" {
+		t.Errorf("status messages = %q", res.StatusMessages)
+	}
+
+	sum := sha256.Sum256(weights)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+
+	if _, ok := srv.Blob(digest); !ok {
+		t.Error("the weights were not uploaded")
+	}
+
+	last, ok := srv.LastRequest("/api/create")
+	if !ok {
+		t.Fatal("no create request was sent")
+	}
+
+	var sent golloom.CreateModelRequest
+	if err := last.Decode(&sent); err != nil {
+		t.Fatal(err)
+	}
+
+	if sent.From != "" || sent.Files["model.gguf"] != digest {
+		t.Errorf("create request from = %q, files = %v; want the uploaded weights", sent.From, sent.Files)
+	}
+
+	if sent.Parameters["num_ctx"] != float64(2048) {
+		t.Errorf("parameters = %v", sent.Parameters)
+	}
+}
@@ -0,0 +1,153 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+// Package modelfile parses, builds, and renders Ollama Modelfiles. A Modelfile is read into
+// an ordered list of typed directives, which can be turned into a golloom.CreateModelRequest
+// and back, and rendered again as canonical text:
+//
+//	mf, err := modelfile.Parse(strings.NewReader(text))
+//	req, err := mf.CreateModelRequest("my-model")
+//	resp, err := client.CreateModel(ctx, req)
+//
+// Modelfiles whose FROM or ADAPTER directives point at local files are created with
+// Modelfile.Create, which uploads those files first.
+package modelfile
+
+import "strings"
+
+// Kind identifies the instruction of a Modelfile directive.
+type Kind string
+
+const (
+	From      Kind = "FROM"      // The base model, or a local weights file or directory.
+	Parameter Kind = "PARAMETER" // A runtime parameter; Name holds the parameter name.
+	Template  Kind = "TEMPLATE"  // The prompt template.
+	System    Kind = "SYSTEM"    // The system message.
+	Adapter   Kind = "ADAPTER"   // A LoRA adapter file or directory.
+	License   Kind = "LICENSE"   // A license text; a Modelfile may hold several.
+	Message   Kind = "MESSAGE"   // A message of the conversation history; Name holds the role.
+)
+
+// kindOrder lists the directive kinds in the order used by canonical rendering.
+var kindOrder = []Kind{
+	From,
+	Adapter,
+	Template,
+	System,
+	Parameter,
+	Message,
+	License,
+}
+
+// messageRoles lists the roles a Message directive may have.
+var messageRoles = []string{"system", "user", "assistant"}
+
+// Directive is a single instruction of a Modelfile.
+type Directive struct {
+	Kind  Kind   // The instruction, e.g. From or Parameter.
+	Name  string // The parameter name for Parameter and the role for Message; empty otherwise.
+	Value string // The argument, with any quotes removed.
+}
+
+// Modelfile is the parsed form of a Modelfile: its directives in source order.
+type Modelfile struct {
+	Directives []Directive
+}
+
+// Add appends a directive and returns m for chaining.
+// Parameters:
+//   - kind: The instruction of the directive.
+//   - name: The parameter name or message role; empty for other kinds.
+//   - value: The argument of the directive.
+//
+// Returns:
+//   - The Modelfile itself.
+func (m *Modelfile) Add(kind Kind, name, value string) *Modelfile {
+	m.Directives = append(m.Directives, Directive{
+		Kind:  kind,
+		Name:  name,
+		Value: value,
+	})
+
+	return m
+}
+
+// Get returns the value of the last directive of the given kind, or an empty string if there is none.
+// For From, Template, and System, which may appear only once, the last one wins.
+func (m *Modelfile) Get(kind Kind) string {
+	for i := len(m.Directives) - 1; i >= 0; i-- {
+		if m.Directives[i].Kind == kind {
+			return m.Directives[i].Value
+		}
+	}
+
+	return ""
+}
+
+// All returns every directive of the given kind, in source order.
+func (m *Modelfile) All(kind Kind) []Directive {
+	var out []Directive
+	for _, d := range m.Directives {
+		if d.Kind == kind {
+			out = append(out, d)
+		}
+	}
+
+	return out
+}
+
+// String renders the Modelfile as canonical text: one directive per line, grouped in the order
+// FROM, ADAPTER, TEMPLATE, SYSTEM, PARAMETER, MESSAGE, LICENSE while keeping the source order
+// within each group. Values are quoted only when needed, and values spanning several lines are
+// written as triple-quoted blocks.
+func (m *Modelfile) String() string {
+	var sb strings.Builder
+	for _, kind := range kindOrder {
+		for _, d := range m.All(kind) {
+			sb.WriteString(d.String())
+			sb.WriteByte('\n')
+		}
+	}
+
+	return sb.String()
+}
+
+// String renders the directive as a single Modelfile instruction.
+func (d Directive) String() string {
+	if d.Name != "" {
+		return string(d.Kind) + " " + d.Name + " " + quote(d.Value)
+	}
+
+	return string(d.Kind) + " " + quote(d.Value)
+}
+
+// quote returns value as it must be written in a Modelfile: bare when it reads back unchanged,
+// double-quoted when it is empty or has surrounding spaces, and triple-quoted when it spans
+// several lines or contains double quotes.
+func quote(value string) string {
+	switch {
+	case strings.ContainsAny(value, "\"\n"):
+		return `"""` + value + `"""`
+
+	case value == "",
+		strings.TrimSpace(value) != value,
+		strings.HasPrefix(value, "#"):
+		return `"` + strings.ReplaceAll(value, `\`, `\\`) + `"`
+	}
+
+	return value
+}
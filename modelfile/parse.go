@@ -0,0 +1,192 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package modelfile
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// ParseError reports a syntax error in a Modelfile together with the line it was found on.
+type ParseError struct {
+	Line    int    // The 1-based line number.
+	Message string // A description of the error.
+}
+
+// Error implements the error interface.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("modelfile: line %d: %s", e.Line, e.Message)
+}
+
+// Parse reads a Modelfile into its directives. Instructions are case-insensitive, blank
+// lines and lines starting with "#" are skipped, and a value may be written bare, in double
+// quotes with backslash escapes, or as a """triple-quoted""" block spanning several lines.
+// As in Ollama, a MESSAGE role must be system, user, or assistant.
+// Parameters:
+//   - r: The reader supplying the Modelfile text.
+//
+// Returns:
+//   - A pointer to the parsed Modelfile.
+//   - A *ParseError describing the first syntax error, or the error returned by r.
+func Parse(r io.Reader) (*Modelfile, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	lines := strings.Split(text, "\n")
+
+	mf := &Modelfile{}
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		word, rest := cutSpace(line)
+		kind := Kind(strings.ToUpper(word))
+
+		if !slices.Contains(kindOrder, kind) {
+			return nil, &ParseError{
+				Line:    i + 1,
+				Message: fmt.Sprintf("unknown instruction %q", word),
+			}
+		}
+
+		var name string
+		if kind == Parameter || kind == Message {
+			name, rest = cutSpace(rest)
+			name = strings.ToLower(name)
+		}
+
+		if kind == Message && !slices.Contains(messageRoles, name) {
+			return nil, &ParseError{
+				Line:    i + 1,
+				Message: fmt.Sprintf("invalid MESSAGE role %q; must be system, user, or assistant", name),
+			}
+		}
+
+		if rest == "" {
+			return nil, &ParseError{
+				Line:    i + 1,
+				Message: fmt.Sprintf("missing argument for %s", kind),
+			}
+		}
+
+		value, last, err := parseValue(lines, i, rest)
+		if err != nil {
+			return nil, err
+		}
+
+		mf.Add(kind, name, value)
+		i = last
+	}
+
+	return mf, nil
+}
+
+// ParseFile reads and parses the Modelfile at path. See Parse for the syntax.
+func ParseFile(path string) (*Modelfile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Parse(f)
+}
+
+// cutSpace splits s at its first run of white space.
+func cutSpace(s string) (string, string) {
+	i := strings.IndexFunc(s, unicode.IsSpace)
+	if i < 0 {
+		return s, ""
+	}
+
+	return s[:i], strings.TrimSpace(s[i:])
+}
+
+// parseValue decodes the value rest found on line i, reading further lines for a triple-quoted
+// block, and returns it together with the index of the last line it occupies.
+func parseValue(
+	lines []string,
+	i int,
+	rest string,
+) (string, int, error) {
+	switch {
+	case strings.HasPrefix(rest, `"""`):
+		var parts []string
+		for j := i; j < len(lines); j++ {
+			text := lines[j]
+			if j == i {
+				text = rest[3:]
+			}
+
+			trimmed := strings.TrimRightFunc(text, unicode.IsSpace)
+			if strings.HasSuffix(trimmed, `"""`) {
+				parts = append(parts, strings.TrimSuffix(trimmed, `"""`))
+				return strings.Join(parts, "\n"), j, nil
+			}
+
+			parts = append(parts, text)
+		}
+
+		return "", i, &ParseError{
+			Line:    i + 1,
+			Message: "unterminated triple-quoted block",
+		}
+
+	case strings.HasPrefix(rest, `"`):
+		var sb strings.Builder
+		for k := 1; k < len(rest); k++ {
+			switch rest[k] {
+			case '\\':
+				if k+1 < len(rest) {
+					k++
+				}
+
+				sb.WriteByte(rest[k])
+
+			case '"':
+				if k != len(rest)-1 {
+					return "", i, &ParseError{
+						Line:    i + 1,
+						Message: "unexpected text after closing quote",
+					}
+				}
+
+				return sb.String(), i, nil
+
+			default:
+				sb.WriteByte(rest[k])
+			}
+		}
+
+		return "", i, &ParseError{
+			Line:    i + 1,
+			Message: "unterminated quoted string",
+		}
+	}
+
+	return rest, i, nil
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package modelfile
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sampleModelfile = "# A sample Modelfile.\r\n" +
+	"from llama3\n" +
+	"\n" +
+	"PARAMETER Temperature 0.7\n" +
+	"PARAMETER stop <|eot_id|>\n" +
+	"PARAMETER stop \"  \"\n" +
+	"TEMPLATE \"\"\"{{ .System }}\n" +
+	"{{ .Prompt }}\"\"\"\n" +
+	"SYSTEM \"You are \\\"helpful\\\".\"\n" +
+	"MESSAGE User Hello\n" +
+	"MESSAGE assistant Hi there\n" +
+	"LICENSE \"\"\"\n" +
+	"MIT\n" +
+	"\"\"\"\n"
+
+func TestParse(t *testing.T) {
+	mf, err := Parse(strings.NewReader(sampleModelfile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Directive{
+		{Kind: From, Value: "llama3"},
+		{Kind: Parameter, Name: "temperature", Value: "0.7"},
+		{Kind: Parameter, Name: "stop", Value: "<|eot_id|>"},
+		{Kind: Parameter, Name: "stop", Value: "  "},
+		{Kind: Template, Value: "{{ .System }}\n{{ .Prompt }}"},
+		{Kind: System, Value: `You are "helpful".`},
+		{Kind: Message, Name: "user", Value: "Hello"},
+		{Kind: Message, Name: "assistant", Value: "Hi there"},
+		{Kind: License, Value: "\nMIT\n"},
+	}
+
+	if !reflect.DeepEqual(mf.Directives, want) {
+		t.Errorf("directives =\n%q\nwant\n%q", mf.Directives, want)
+	}
+
+	if got := mf.Get(From); got != "llama3" {
+		t.Errorf("FROM = %q", got)
+	}
+
+	if got := len(mf.All(Parameter)); got != 3 {
+		t.Errorf("%d parameters, want 3", got)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		line    int
+		message string
+	}{
+		{"unknown instruction", "FROM llama3\nRUN x", 2, `unknown instruction "RUN"`},
+		{"missing argument", "FROM", 1, "missing argument for FROM"},
+		{"missing parameter value", "PARAMETER stop", 1, "missing argument for PARAMETER"},
+		{"unterminated block", "FROM llama3\nSYSTEM \"\"\"never\nends", 2, "unterminated triple-quoted block"},
+		{"unterminated quote", `SYSTEM "open`, 1, "unterminated quoted string"},
+		{"text after quote", `SYSTEM "a" b`, 1, "unexpected text after closing quote"},
+		{"invalid role", "FROM llama3\n\nMESSAGE tool result", 3, `invalid MESSAGE role "tool"`},
+	}
+
+	for _, tt := range tests {
+		_, err := Parse(strings.NewReader(tt.text))
+
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("%s: err = %v, want a *ParseError", tt.name, err)
+			continue
+		}
+
+		if parseErr.Line != tt.line || !strings.HasPrefix(parseErr.Message, tt.message) {
+			t.Errorf("%s: error = %v, want line %d: %s", tt.name, err, tt.line, tt.message)
+		}
+	}
+}
+
+func TestModelfileString(t *testing.T) {
+	mf := &Modelfile{}
+	mf.Add(License, "", "MIT").
+		Add(Parameter, "stop", "#").
+		Add(Message, "user", "").
+		Add(System, "", "Say \"hi\".").
+		Add(From, "", "llama3")
+
+	want := "FROM llama3\n" +
+		"SYSTEM \"\"\"Say \"hi\".\"\"\"\n" +
+		"PARAMETER stop \"#\"\n" +
+		"MESSAGE user \"\"\n" +
+		"LICENSE MIT\n"
+
+	if got := mf.String(); got != want {
+		t.Errorf("String() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestModelfileRoundTrip(t *testing.T) {
+	mf, err := Parse(strings.NewReader(sampleModelfile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	again, err := Parse(strings.NewReader(mf.String()))
+	if err != nil {
+		t.Fatalf("parsing the rendered Modelfile: %v\n%s", err, mf.String())
+	}
+
+	for _, kind := range kindOrder {
+		if got, want := again.All(kind), mf.All(kind); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s directives after a round trip = %q, want %q", kind, got, want)
+		}
+	}
+
+	if again.String() != mf.String() {
+		t.Errorf("rendering is not stable:\n%s\nthen\n%s", mf.String(), again.String())
+	}
+}
@@ -0,0 +1,686 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloom
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// OpenAIClient talks to servers speaking the OpenAI-compatible dialect, such as Ollama's /v1
+// endpoints, vLLM, or the llama.cpp server, using golloom's own Chat, Message, and EmbedResult types.
+// It implements Chatter and Embedder, so code written against those interfaces works with both
+// server types. Requests go through the same plumbing as Client, including default headers and
+// the retry policy; an API key is set with WithBearerToken.
+type OpenAIClient struct {
+	client *Client
+}
+
+var (
+	_ Chatter  = (*OpenAIClient)(nil)
+	_ Embedder = (*OpenAIClient)(nil)
+)
+
+// NewOpenAIClient creates an OpenAIClient configured by the same options as NewClientWithOptions.
+// The base URL must not include the "/v1" prefix, which is added to every request.
+// Parameters:
+//   - opts: A list of Option values configuring the base URL, HTTP client, headers, and so on.
+//
+// Returns:
+//   - A pointer to the new OpenAIClient.
+//   - An error if any option fails to apply.
+func NewOpenAIClient(opts ...Option) (*OpenAIClient, error) {
+	c, err := NewClientWithOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OpenAIClient{client: c}, nil
+}
+
+// openAIMessage is a chat message in the OpenAI format. Content is a string, or a list
+// of parts when the message carries images.
+type openAIMessage struct {
+	Role       string           `json:"role"`
+	Content    interface{}      `json:"content"`
+	ToolCalls  []openAIToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string           `json:"tool_call_id,omitempty"`
+}
+
+// openAIToolCall is a tool invocation in the OpenAI format, whose arguments are a JSON string.
+// In streamed deltas, Index identifies the call a fragment belongs to.
+type openAIToolCall struct {
+	Index    *int   `json:"index,omitempty"`
+	ID       string `json:"id,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Function struct {
+		Name      string `json:"name,omitempty"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+// openAIChatResponse is a chat completion, or one chunk of a streamed completion.
+type openAIChatResponse struct {
+	Model   string `json:"model"`
+	Created int64  `json:"created"`
+	Choices []struct {
+		Message struct {
+			Role      string           `json:"role"`
+			Content   string           `json:"content"`
+			ToolCalls []openAIToolCall `json:"tool_calls"`
+		} `json:"message"`
+		Delta struct {
+			Role      string           `json:"role"`
+			Content   string           `json:"content"`
+			ToolCalls []openAIToolCall `json:"tool_calls"`
+		} `json:"delta"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *openAIUsage `json:"usage"`
+}
+
+// openAIUsage reports the token counts of a request.
+type openAIUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+// openAIEmbedResponse is the response of the /v1/embeddings endpoint.
+type openAIEmbedResponse struct {
+	Model string `json:"model"`
+	Data  []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+	Usage *openAIUsage `json:"usage"`
+}
+
+// openAIOptionNames maps the Ollama option names that have an OpenAI counterpart
+// to the name of the corresponding request field. Other options are not sent.
+var openAIOptionNames = map[string]string{
+	"temperature":       "temperature",
+	"top_p":             "top_p",
+	"seed":              "seed",
+	"stop":              "stop",
+	"num_predict":       "max_tokens",
+	"frequency_penalty": "frequency_penalty",
+	"presence_penalty":  "presence_penalty",
+}
+
+// Chat sends a chat request to the /v1/chat/completions endpoint and maps the completion
+// back into a ModelResponse. Options without an OpenAI counterpart and KeepAlive are ignored.
+// Parameters:
+//   - ctx: A context for controlling cancellation and timeouts for the request.
+//   - req: A pointer to a Chat struct containing the conversation history and optional configuration.
+//
+// Returns:
+//   - A pointer to a ModelResponse carrying the first choice of the completion.
+//   - An error if the HTTP request or response decoding fails.
+func (c *OpenAIClient) Chat(
+	ctx context.Context,
+	req *Chat,
+) (*ModelResponse, error) {
+	resp, err := c.post(
+		ctx,
+		"/v1/chat/completions",
+		openAIChatBody(req, false),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var completion openAIChatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&completion); err != nil {
+		return nil, err
+	}
+
+	if len(completion.Choices) == 0 {
+		return nil, fmt.Errorf("chat completion has no choices")
+	}
+
+	choice := completion.Choices[0]
+	toolCalls, err := fromOpenAIToolCalls(choice.Message.ToolCalls)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &ModelResponse{
+		Model:     completion.Model,
+		CreatedAt: openAITime(completion.Created),
+		Message: Message{
+			Role:      choice.Message.Role,
+			Content:   choice.Message.Content,
+			ToolCalls: toolCalls,
+		},
+		Done:       true,
+		DoneReason: choice.FinishReason,
+	}
+
+	out.setUsage(completion.Usage)
+	return out, nil
+}
+
+// ChatStream sends a chat request to the /v1/chat/completions endpoint and streams the reply
+// from its server-sent events. Content arrives chunk by chunk; tool calls, whose arguments are
+// streamed in fragments, are assembled and delivered with the final chunk, which is marked done
+// and carries the token counts when the server reports them.
+// Parameters:
+//   - ctx: A context for controlling cancellation and timeouts for the request.
+//   - req: A pointer to a Chat struct containing the conversation history and optional configuration.
+//
+// Returns:
+//   - An iterator yielding each ModelResponse chunk, or an error that ended the stream.
+func (c *OpenAIClient) ChatStream(
+	ctx context.Context,
+	req *Chat,
+) iter.Seq2[*ModelResponse, error] {
+	return func(yield func(*ModelResponse, error) bool) {
+		resp, err := c.post(
+			ctx,
+			"/v1/chat/completions",
+			openAIChatBody(req, true),
+		)
+
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer resp.Body.Close()
+
+		final := &ModelResponse{Done: true}
+		calls := make(map[int]*openAIToolCall)
+
+		for chunk, err := range decodeSSE[openAIChatResponse](ctx, resp) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			if chunk.Model != "" {
+				final.Model = chunk.Model
+			}
+
+			if chunk.Created != 0 {
+				final.CreatedAt = openAITime(chunk.Created)
+			}
+
+			final.setUsage(chunk.Usage)
+
+			if len(chunk.Choices) == 0 {
+				continue
+			}
+
+			delta := chunk.Choices[0].Delta
+			if delta.Role != "" {
+				final.Message.Role = delta.Role
+			}
+
+			if reason := chunk.Choices[0].FinishReason; reason != "" {
+				final.DoneReason = reason
+			}
+
+			for i, fragment := range delta.ToolCalls {
+				index := i
+				if fragment.Index != nil {
+					index = *fragment.Index
+				}
+
+				call, ok := calls[index]
+				if !ok {
+					call = &openAIToolCall{}
+					calls[index] = call
+				}
+
+				if fragment.Function.Name != "" {
+					call.Function.Name = fragment.Function.Name
+				}
+
+				call.Function.Arguments += fragment.Function.Arguments
+			}
+
+			if delta.Content == "" {
+				continue
+			}
+
+			if !yield(&ModelResponse{
+				Model:     final.Model,
+				CreatedAt: final.CreatedAt,
+				Message: Message{
+					Role:    final.Message.Role,
+					Content: delta.Content,
+				},
+			}, nil) {
+				return
+			}
+		}
+
+		indexes := make([]int, 0, len(calls))
+		for index := range calls {
+			indexes = append(indexes, index)
+		}
+		sort.Ints(indexes)
+
+		assembled := make([]openAIToolCall, 0, len(indexes))
+		for _, index := range indexes {
+			assembled = append(assembled, *calls[index])
+		}
+
+		toolCalls, err := fromOpenAIToolCalls(assembled)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		final.Message.ToolCalls = toolCalls
+		yield(final, nil)
+	}
+}
+
+// Embed generates an embedding for input through the /v1/embeddings endpoint.
+// The options are not sent, as the OpenAI dialect has no counterpart for them.
+// Parameters:
+//   - ctx: A context.Context object for managing request deadlines and cancellations.
+//   - model: The name or identifier of the model to use for generating the embedding.
+//   - input: The input data for which the embedding is to be generated.
+//   - options: Ignored; accepted so OpenAIClient implements Embedder.
+//
+// Returns:
+//   - A pointer to an EmbedResult whose Embeddings hold the single vector.
+//   - An error if the request fails or the server returns an unexpected response.
+func (c *OpenAIClient) Embed(
+	ctx context.Context,
+	model, input string,
+	options map[string]interface{},
+) (*EmbedResult, error) {
+	return c.embed(ctx, model, []string{input})
+}
+
+// EmbedBatch generates embeddings for many inputs through the /v1/embeddings endpoint,
+// batching and bounding concurrency like Client.EmbedBatch. Truncate, KeepAlive, and
+// Options are not sent.
+// Parameters:
+//   - ctx: A context.Context object for managing request deadlines and cancellations.
+//   - model: The name or identifier of the model to use for generating the embeddings.
+//   - inputs: The texts to embed.
+//   - opts: Optional batching settings; nil uses the defaults.
+//
+// Returns:
+//   - A pointer to an EmbedResult whose Embeddings hold one vector per input.
+//   - An error if any batch fails or the server returns an unexpected number of vectors.
+func (c *OpenAIClient) EmbedBatch(
+	ctx context.Context,
+	model string,
+	inputs []string,
+	opts *EmbedBatchOptions,
+) (*EmbedResult, error) {
+	if opts == nil {
+		opts = &EmbedBatchOptions{}
+	}
+
+	return embedInBatches(
+		ctx,
+		model,
+		inputs,
+		opts,
+		func(ctx context.Context, batch []string) (*EmbedResult, error) {
+			return c.embed(ctx, model, batch)
+		},
+	)
+}
+
+// embed sends inputs to the /v1/embeddings endpoint and orders the returned vectors by index.
+func (c *OpenAIClient) embed(
+	ctx context.Context,
+	model string,
+	inputs []string,
+) (*EmbedResult, error) {
+	resp, err := c.post(
+		ctx,
+		"/v1/embeddings",
+		map[string]interface{}{
+			"model": model,
+			"input": inputs,
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var embedResp openAIEmbedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&embedResp); err != nil {
+		return nil, err
+	}
+
+	out := &EmbedResult{
+		Model:      embedResp.Model,
+		CreatedAt:  time.Now().UTC(),
+		Embeddings: make([][]float32, len(embedResp.Data)),
+	}
+
+	for _, d := range embedResp.Data {
+		if d.Index < 0 || d.Index >= len(out.Embeddings) {
+			return nil, fmt.Errorf("embedding index %d out of range", d.Index)
+		}
+
+		out.Embeddings[d.Index] = d.Embedding
+	}
+
+	if embedResp.Usage != nil {
+		out.PromptEvalCount = embedResp.Usage.PromptTokens
+	}
+
+	return out, nil
+}
+
+// post sends body as JSON to the given path and checks the status of the response,
+// whose Body must be closed by the caller.
+func (c *OpenAIClient) post(
+	ctx context.Context,
+	path string,
+	body interface{},
+) (*http.Response, error) {
+	rel := &url.URL{Path: path}
+	u := c.client.resolve(rel)
+
+	return c.client.sendStreamRequest(
+		ctx,
+		"POST",
+		u.String(),
+		body,
+	)
+}
+
+// openAITime converts the Unix timestamp of a completion into a time.Time,
+// leaving it zero when the server reports none.
+func openAITime(created int64) time.Time {
+	if created == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(created, 0).UTC()
+}
+
+// setUsage copies the token counts reported by an OpenAI-compatible server.
+func (r *ModelResponse) setUsage(usage *openAIUsage) {
+	if usage == nil {
+		return
+	}
+
+	r.PromptEvalCount = usage.PromptTokens
+	r.EvalCount = usage.CompletionTokens
+}
+
+// openAIChatBody translates a Chat into the body of a /v1/chat/completions request.
+func openAIChatBody(req *Chat, stream bool) map[string]interface{} {
+	body := map[string]interface{}{
+		"model":    req.Model,
+		"messages": toOpenAIMessages(req.Messages),
+	}
+
+	if len(req.Tools) > 0 {
+		body["tools"] = req.Tools
+	}
+
+	if stream {
+		body["stream"] = true
+		body["stream_options"] = map[string]interface{}{
+			"include_usage": true,
+		}
+	}
+
+	switch format := req.Format.(type) {
+	case nil:
+
+	case string:
+		if format == "json" {
+			body["response_format"] = map[string]interface{}{
+				"type": "json_object",
+			}
+		}
+
+	default:
+		body["response_format"] = map[string]interface{}{
+			"type": "json_schema",
+			"json_schema": map[string]interface{}{
+				"name":   "response",
+				"schema": format,
+			},
+		}
+	}
+
+	for name, value := range req.Options {
+		if field, ok := openAIOptionNames[name]; ok {
+			body[field] = value
+		}
+	}
+
+	return body
+}
+
+// toOpenAIMessages translates a conversation into OpenAI messages. Since golloom messages carry
+// no tool call IDs, IDs are derived from each call's position, and every "tool" message is linked
+// to the earliest unanswered call with a matching name, or else to the earliest unanswered call.
+func toOpenAIMessages(messages []Message) []openAIMessage {
+	out := make([]openAIMessage, 0, len(messages))
+
+	var pending []openAIToolCall
+	for i, m := range messages {
+		msg := openAIMessage{
+			Role:    m.Role,
+			Content: openAIContent(m),
+		}
+
+		if len(m.ToolCalls) > 0 {
+			pending = pending[:0]
+		}
+
+		for j, call := range m.ToolCalls {
+			args := call.Function.Arguments
+			if args == nil {
+				args = map[string]interface{}{}
+			}
+
+			data, _ := json.Marshal(args)
+
+			tc := openAIToolCall{
+				ID:   fmt.Sprintf("call_%d_%d", i, j),
+				Type: "function",
+			}
+
+			tc.Function.Name = call.Function.Name
+			tc.Function.Arguments = string(data)
+
+			msg.ToolCalls = append(msg.ToolCalls, tc)
+			pending = append(pending, tc)
+		}
+
+		if m.Role == "tool" && len(pending) > 0 {
+			match := 0
+			for k, tc := range pending {
+				if tc.Function.Name == m.ToolName {
+					match = k
+					break
+				}
+			}
+
+			msg.ToolCallID = pending[match].ID
+			pending = append(pending[:match], pending[match+1:]...)
+		}
+
+		out = append(out, msg)
+	}
+
+	return out
+}
+
+// openAIContent returns the content of a message as a string, or as a list of text and
+// image parts when the message carries images. Base64 images are sent as data URLs.
+func openAIContent(m Message) interface{} {
+	if len(m.Images) == 0 {
+		return m.Content
+	}
+
+	parts := []map[string]interface{}{
+		{"type": "text", "text": m.Content},
+	}
+
+	for _, img := range m.Images {
+		imageURL := img
+		if !strings.HasPrefix(img, "data:") &&
+			!strings.HasPrefix(img, "http://") &&
+			!strings.HasPrefix(img, "https://") {
+			data, _ := base64.StdEncoding.DecodeString(img)
+			imageURL = "data:" + http.DetectContentType(data) + ";base64," + img
+		}
+
+		parts = append(parts, map[string]interface{}{
+			"type":      "image_url",
+			"image_url": map[string]interface{}{"url": imageURL},
+		})
+	}
+
+	return parts
+}
+
+// fromOpenAIToolCalls decodes the JSON-string arguments of OpenAI tool calls into ToolCalls.
+func fromOpenAIToolCalls(calls []openAIToolCall) ([]ToolCall, error) {
+	if len(calls) == 0 {
+		return nil, nil
+	}
+
+	out := make([]ToolCall, 0, len(calls))
+	for i, call := range calls {
+		args := map[string]interface{}{}
+		if strings.TrimSpace(call.Function.Arguments) != "" {
+			if err := json.Unmarshal([]byte(call.Function.Arguments), &args); err != nil {
+				return nil, fmt.Errorf(
+					"decoding arguments of tool call %q: %w",
+					call.Function.Name,
+					err,
+				)
+			}
+		}
+
+		out = append(out, ToolCall{
+			Function: ToolCallFunction{
+				Index:     i,
+				Name:      call.Function.Name,
+				Arguments: args,
+			},
+		})
+	}
+
+	return out, nil
+}
+
+// decodeSSE reads the server-sent events of resp and yields the "data" payload of every event
+// decoded as T, until the "[DONE]" sentinel or the end of the body. A payload carrying an "error"
+// field terminates the stream with an *APIError, and a read failure caused by cancellation is
+// reported as the context's error.
+// Parameters:
+//   - ctx: The context.Context governing the underlying request.
+//   - resp: The response whose body provides the event stream.
+//
+// Returns:
+//   - An iterator over the decoded events and any error that ended the stream.
+func decodeSSE[T any](
+	ctx context.Context,
+	resp *http.Response,
+) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		reader := bufio.NewReader(resp.Body)
+		var data bytes.Buffer
+
+		for {
+			line, err := reader.ReadBytes('\n')
+			if err != nil && !errors.Is(err, io.EOF) {
+				if ctxErr := ctx.Err(); ctxErr != nil {
+					err = ctxErr
+				}
+
+				yield(nil, fmt.Errorf("error decoding stream: %w", err))
+				return
+			}
+
+			eof := err != nil
+			line = bytes.TrimRight(line, "\r\n")
+
+			if payload, ok := bytes.CutPrefix(line, []byte("data:")); ok {
+				if data.Len() > 0 {
+					data.WriteByte('\n')
+				}
+
+				data.Write(bytes.TrimPrefix(payload, []byte(" ")))
+			}
+
+			if (len(line) == 0 || eof) && data.Len() > 0 {
+				payload := bytes.Clone(data.Bytes())
+				data.Reset()
+
+				if string(payload) == "[DONE]" {
+					return
+				}
+
+				var streamErr struct {
+					Error json.RawMessage `json:"error"`
+				}
+
+				if err := json.Unmarshal(payload, &streamErr); err == nil {
+					if msg := errorMessage(streamErr.Error); msg != "" {
+						yield(nil, &APIError{
+							StatusCode: resp.StatusCode,
+							Message:    msg,
+							Endpoint:   responseEndpoint(resp),
+						})
+						return
+					}
+				}
+
+				var event T
+				if err := json.Unmarshal(payload, &event); err != nil {
+					yield(nil, fmt.Errorf("error decoding stream: %w", err))
+					return
+				}
+
+				if !yield(&event, nil) {
+					return
+				}
+			}
+
+			if eof {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,367 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloom
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// openAIServer starts a server answering every request with handle and returns an
+// OpenAIClient for it with retries disabled.
+func openAIServer(
+	t *testing.T,
+	handle func(w http.ResponseWriter, r *http.Request),
+) *OpenAIClient {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(handle))
+	t.Cleanup(srv.Close)
+
+	client, err := NewOpenAIClient(
+		WithBaseURL(srv.URL),
+		WithRetryPolicy(nil),
+	)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return client
+}
+
+// sseResponse builds an http.Response whose body is the given event stream.
+func sseResponse(stream string) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(stream)),
+	}
+}
+
+func TestOpenAIChat(t *testing.T) {
+	var body map[string]interface{}
+
+	client := openAIServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+
+		json.NewDecoder(r.Body).Decode(&body)
+		fmt.Fprint(w, `{
+			"model": "gpt-test",
+			"created": 1700000000,
+			"choices": [{
+				"message": {
+					"role": "assistant",
+					"content": "",
+					"tool_calls": [{"id": "x", "type": "function", "function": {"name": "add", "arguments": "{\"a\":1}"}}]
+				},
+				"finish_reason": "tool_calls"
+			}],
+			"usage": {"prompt_tokens": 12, "completion_tokens": 3}
+		}`)
+	})
+
+	resp, err := client.Chat(context.Background(), &Chat{
+		Model:    "gpt-test",
+		Messages: []Message{{Role: "user", Content: "add"}},
+		Format:   "json",
+		Options:  map[string]interface{}{"num_predict": 5, "num_ctx": 4096},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if body["max_tokens"] != float64(5) || body["num_ctx"] != nil {
+		t.Errorf("request options = %v, want num_predict as max_tokens only", body)
+	}
+
+	if !reflect.DeepEqual(body["response_format"], map[string]interface{}{"type": "json_object"}) {
+		t.Errorf("response_format = %v", body["response_format"])
+	}
+
+	if resp.Model != "gpt-test" || resp.DoneReason != "tool_calls" || resp.CreatedAt.Unix() != 1700000000 {
+		t.Errorf("response = %+v", resp)
+	}
+
+	if resp.PromptEvalCount != 12 || resp.EvalCount != 3 {
+		t.Errorf("usage = %d, %d", resp.PromptEvalCount, resp.EvalCount)
+	}
+
+	calls := resp.Message.ToolCalls
+	if len(calls) != 1 || calls[0].Function.Name != "add" || calls[0].Function.Arguments["a"] != float64(1) {
+		t.Errorf("tool calls = %+v", calls)
+	}
+}
+
+func TestOpenAIChatStream(t *testing.T) {
+	client := openAIServer(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, strings.Join([]string{
+			`data: {"model":"gpt-test","created":1700000000,"choices":[{"delta":{"role":"assistant","content":"Hel"}}]}`,
+			``,
+			`: keep-alive comment`,
+			`data: {"choices":[{"delta":{"content":"lo"}}]}`,
+			``,
+			`data: {"choices":[{"delta":{"tool_calls":[{"index":1,"id":"b","function":{"name":"mul","arguments":"{\"x\":"}}]}}]}`,
+			``,
+			`data: {"choices":[{"delta":{"tool_calls":[{"index":0,"id":"a","function":{"name":"add","arguments":""}}]}}]}`,
+			``,
+			`data: {"choices":[{"delta":{"tool_calls":[{"index":1,"function":{"arguments":"2}"}},{"index":0,"function":{"arguments":"{\"a\":1}"}}]}}]}`,
+			``,
+			`data: {"choices":[{"delta":{},"finish_reason":"tool_calls"}]}`,
+			``,
+			`data: {"choices":[],"usage":{"prompt_tokens":7,"completion_tokens":4}}`,
+			``,
+			`data: [DONE]`,
+			``,
+			`data: {"choices":[{"delta":{"content":"after done"}}]}`,
+			``,
+		}, "\n"))
+	})
+
+	var parts []string
+	var final *ModelResponse
+
+	for chunk, err := range client.ChatStream(context.Background(), &Chat{Model: "gpt-test"}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if chunk.Done {
+			final = chunk
+			continue
+		}
+
+		if chunk.Model != "gpt-test" {
+			t.Errorf("chunk model = %q", chunk.Model)
+		}
+
+		parts = append(parts, chunk.Message.Content)
+	}
+
+	if !reflect.DeepEqual(parts, []string{"Hel", "lo"}) {
+		t.Errorf("content chunks = %q", parts)
+	}
+
+	if final == nil {
+		t.Fatal("no final chunk")
+	}
+
+	if final.Model != "gpt-test" {
+		t.Errorf("final model = %q; a chunk without a model blanked it", final.Model)
+	}
+
+	if final.DoneReason != "tool_calls" || final.PromptEvalCount != 7 || final.EvalCount != 4 {
+		t.Errorf("final chunk = %+v", final)
+	}
+
+	calls := final.Message.ToolCalls
+	if len(calls) != 2 {
+		t.Fatalf("tool calls = %+v, want 2", calls)
+	}
+
+	if calls[0].Function.Name != "add" || calls[0].Function.Arguments["a"] != float64(1) {
+		t.Errorf("first call = %+v", calls[0])
+	}
+
+	if calls[1].Function.Name != "mul" || calls[1].Function.Arguments["x"] != float64(2) || calls[1].Function.Index != 1 {
+		t.Errorf("second call = %+v", calls[1])
+	}
+}
+
+func TestOpenAIErrorStatus(t *testing.T) {
+	client := openAIServer(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"error":{"message":"invalid api key","type":"invalid_request_error"}}`)
+	})
+
+	var apiErr *APIError
+
+	_, err := client.Chat(context.Background(), &Chat{Model: "gpt-test"})
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized || apiErr.Message != "invalid api key" {
+		t.Errorf("chat error = %v, want a 401 APIError carrying the message", err)
+	}
+
+	for _, err := range client.ChatStream(context.Background(), &Chat{Model: "gpt-test"}) {
+		if !errors.As(err, &apiErr) || apiErr.Message != "invalid api key" {
+			t.Errorf("stream error = %v, want the APIError", err)
+		}
+	}
+}
+
+func TestDecodeSSE(t *testing.T) {
+	type event struct {
+		N int `json:"n"`
+	}
+
+	stream := "data: {\"n\":\r\ndata: 1}\r\n\r\n" +
+		"event: ignored\n" +
+		"data:{\"n\":2}\n\n" +
+		"data: {\"n\":3}"
+
+	var got []int
+	for ev, err := range decodeSSE[event](context.Background(), sseResponse(stream)) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got = append(got, ev.N)
+	}
+
+	if !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("events = %v, want [1 2 3]", got)
+	}
+
+	stream = "data: {\"n\":1}\n\n" +
+		"data: {\"error\":\"out of memory\"}\n\n" +
+		"data: {\"n\":2}\n\n"
+
+	got = nil
+
+	var streamErr error
+	for ev, err := range decodeSSE[event](context.Background(), sseResponse(stream)) {
+		if err != nil {
+			streamErr = err
+			break
+		}
+
+		got = append(got, ev.N)
+	}
+
+	var apiErr *APIError
+	if !reflect.DeepEqual(got, []int{1}) || !errors.As(streamErr, &apiErr) || apiErr.Message != "out of memory" {
+		t.Errorf("events = %v, err = %v; want one event and the error payload", got, streamErr)
+	}
+
+	for _, err := range decodeSSE[event](context.Background(), sseResponse("data: {broken\n\n")) {
+		if err == nil || !strings.Contains(err.Error(), "error decoding stream") {
+			t.Errorf("err = %v, want a decoding error", err)
+		}
+	}
+}
+
+func TestToOpenAIMessages(t *testing.T) {
+	call := func(name string) ToolCall {
+		return ToolCall{Function: ToolCallFunction{Name: name}}
+	}
+
+	out := toOpenAIMessages([]Message{
+		{Role: "user", Content: "go"},
+		{Role: "assistant", ToolCalls: []ToolCall{call("a"), call("b"), call("a")}},
+		{Role: "tool", ToolName: "b", Content: "1"},
+		{Role: "tool", ToolName: "a", Content: "2"},
+		{Role: "tool", Content: "3"},
+		{Role: "assistant", ToolCalls: []ToolCall{call("c")}},
+		{Role: "tool", ToolName: "unknown", Content: "4"},
+	})
+
+	var ids []string
+	for _, tc := range out[1].ToolCalls {
+		ids = append(ids, tc.ID)
+
+		if tc.Type != "function" || tc.Function.Arguments != "{}" {
+			t.Errorf("tool call = %+v, want a function with empty arguments", tc)
+		}
+	}
+
+	if !reflect.DeepEqual(ids, []string{"call_1_0", "call_1_1", "call_1_2"}) {
+		t.Errorf("tool call IDs = %v", ids)
+	}
+
+	var links []string
+	for _, msg := range out {
+		if msg.Role == "tool" {
+			links = append(links, msg.ToolCallID)
+		}
+	}
+
+	want := []string{"call_1_1", "call_1_0", "call_1_2", "call_5_0"}
+	if !reflect.DeepEqual(links, want) {
+		t.Errorf("tool_call_id links = %v, want %v", links, want)
+	}
+}
+
+func TestOpenAIEmbedBatch(t *testing.T) {
+	var mu sync.Mutex
+	var batches [][]string
+
+	client := openAIServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/embeddings" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+
+		var body struct {
+			Input []string `json:"input"`
+		}
+
+		json.NewDecoder(r.Body).Decode(&body)
+
+		mu.Lock()
+		batches = append(batches, body.Input)
+		mu.Unlock()
+
+		type datum struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		}
+
+		data := make([]datum, len(body.Input))
+		for i, input := range body.Input {
+			j := len(body.Input) - 1 - i
+			data[j] = datum{Index: i, Embedding: []float32{float32(input[0])}}
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"model": "embedder",
+			"data":  data,
+			"usage": map[string]int{"prompt_tokens": len(body.Input)},
+		})
+	})
+
+	inputs := []string{"a", "b", "c", "d", "e"}
+
+	res, err := client.EmbedBatch(context.Background(), "embedder", inputs, &EmbedBatchOptions{BatchSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(batches) != 3 {
+		t.Errorf("sent %d batches, want 3", len(batches))
+	}
+
+	for i, input := range inputs {
+		if len(res.Embeddings[i]) != 1 || res.Embeddings[i][0] != float32(input[0]) {
+			t.Errorf("embedding %d = %v, want the vector of %q", i, res.Embeddings[i], input)
+		}
+	}
+
+	if res.Model != "embedder" || res.PromptEvalCount != 5 {
+		t.Errorf("model = %q, prompt eval count = %d", res.Model, res.PromptEvalCount)
+	}
+}
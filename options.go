@@ -0,0 +1,126 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloom
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Option configures a Client created by NewClientWithOptions.
+// Options are applied in the order they are given.
+type Option func(*Client) error
+
+// WithBaseURL sets the base URL of the server to which requests will be sent.
+// A "unix://" URL is handled by WithUnixSocket; everything after "unix://" is the socket path,
+// so both "unix:///run/ollama.sock" and the relative "unix://ollama.sock" are accepted.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) error {
+		parsed, err := url.Parse(baseURL)
+		if err != nil {
+			return err
+		}
+
+		if parsed.Scheme == "unix" {
+			return WithUnixSocket(parsed.Opaque + parsed.Host + parsed.Path)(c)
+		}
+
+		c.BaseURL = parsed
+		return nil
+	}
+}
+
+// WithHTTPClient replaces the HTTP client used to make requests.
+// Options applied afterwards, such as WithTimeout, operate on a copy of it.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) error {
+		if httpClient == nil {
+			return fmt.Errorf("http client must not be nil")
+		}
+
+		c.HTTPClient = httpClient
+		return nil
+	}
+}
+
+// WithTransport sets the http.RoundTripper used by the client's HTTP client.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) error {
+		hc := *c.HTTPClient
+		hc.Transport = transport
+
+		c.HTTPClient = &hc
+		return nil
+	}
+}
+
+// WithTimeout sets the overall timeout of every HTTP request, including the time spent
+// reading streamed responses. A zero duration disables the timeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) error {
+		if timeout < 0 {
+			return fmt.Errorf("timeout must not be negative: %s", timeout)
+		}
+
+		hc := *c.HTTPClient
+		hc.Timeout = timeout
+
+		c.HTTPClient = &hc
+		return nil
+	}
+}
+
+// WithHeader adds a default header sent with every request.
+// Calling it repeatedly with the same key adds multiple values.
+func WithHeader(key, value string) Option {
+	return func(c *Client) error {
+		if c.Header == nil {
+			c.Header = make(http.Header)
+		}
+
+		c.Header.Add(key, value)
+		return nil
+	}
+}
+
+// WithBearerToken sets the Authorization header to a bearer token,
+// as expected by reverse proxies placed in front of Ollama.
+func WithBearerToken(token string) Option {
+	return func(c *Client) error {
+		if c.Header == nil {
+			c.Header = make(http.Header)
+		}
+
+		c.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) error {
+		if c.Header == nil {
+			c.Header = make(http.Header)
+		}
+
+		c.Header.Set("User-Agent", userAgent)
+		return nil
+	}
+}
@@ -0,0 +1,474 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// PoolStrategy selects how a Pool orders its hosts for each request.
+type PoolStrategy int
+
+const (
+	// PoolRoundRobin cycles through the healthy hosts in turn.
+	PoolRoundRobin PoolStrategy = iota
+	// PoolLeastInFlight prefers the healthy host with the fewest requests in progress.
+	PoolLeastInFlight
+	// PoolModelAffinity prefers healthy hosts that already have the requested model loaded,
+	// as reported by ProcessStatus, and otherwise behaves like PoolLeastInFlight.
+	PoolModelAffinity
+)
+
+// PoolOptions configures a Pool created by NewPool. The zero value uses round robin,
+// checks health every 30 seconds with a 5-second timeout, and fails over on any error
+// that is not caused by the request itself.
+type PoolOptions struct {
+	// Strategy chooses the order in which hosts are tried.
+	Strategy PoolStrategy
+	// HealthCheckInterval is the time between active health checks; a negative value disables them.
+	HealthCheckInterval time.Duration
+	// HealthCheckTimeout bounds each health check request.
+	HealthCheckTimeout time.Duration
+	// ShouldFailover decides whether a failed request is retried on the next host.
+	// When nil, every error fails over except context cancellation and client errors
+	// other than 404 and 429.
+	ShouldFailover func(error) bool
+}
+
+// Pool spreads chat, generate, and embed requests across several Ollama hosts. Hosts are
+// checked actively with Version, marked down when a request to them fails at the transport
+// level, and skipped while down unless no host is healthy. A failed request fails over to the
+// next host; streams fail over only before their first chunk. A Pool is safe for concurrent use.
+type Pool struct {
+	hosts    []*poolHost
+	strategy PoolStrategy
+	failover func(error) bool
+	timeout  time.Duration
+
+	next atomic.Uint64
+	stop chan struct{}
+	wg   sync.WaitGroup
+	once sync.Once
+}
+
+var (
+	_ Chatter   = (*Pool)(nil)
+	_ Generator = (*Pool)(nil)
+	_ Embedder  = (*Pool)(nil)
+)
+
+// poolHost tracks the state of a single host in a Pool.
+type poolHost struct {
+	client   *Client
+	healthy  atomic.Bool
+	inFlight atomic.Int64
+
+	mu     sync.RWMutex
+	loaded map[string]bool
+}
+
+// PoolHostStatus is a snapshot of the state of a host in a Pool.
+type PoolHostStatus struct {
+	Client   *Client  // The client used to reach the host.
+	Healthy  bool     // Whether the host passed its last health check and has not failed since.
+	InFlight int64    // The number of requests currently in progress on the host.
+	Loaded   []string // The models known to be loaded on the host.
+}
+
+// NewPool creates a Pool over the given clients and starts its health checks.
+// The caller must call Close to stop them.
+// Parameters:
+//   - clients: The clients of the hosts in the pool, one per host.
+//   - opts: Optional strategy and health check settings; nil uses the defaults.
+//
+// Returns:
+//   - A pointer to the new Pool; every host starts out healthy.
+//   - An error if no clients are given.
+func NewPool(
+	clients []*Client,
+	opts *PoolOptions,
+) (*Pool, error) {
+	if len(clients) == 0 {
+		return nil, fmt.Errorf("pool needs at least one client")
+	}
+
+	if opts == nil {
+		opts = &PoolOptions{}
+	}
+
+	p := &Pool{
+		strategy: opts.Strategy,
+		failover: opts.ShouldFailover,
+		timeout:  opts.HealthCheckTimeout,
+		stop:     make(chan struct{}),
+	}
+
+	if p.failover == nil {
+		p.failover = shouldFailover
+	}
+
+	if p.timeout <= 0 {
+		p.timeout = 5 * time.Second
+	}
+
+	for _, c := range clients {
+		h := &poolHost{
+			client: c,
+			loaded: make(map[string]bool),
+		}
+
+		h.healthy.Store(true)
+		p.hosts = append(p.hosts, h)
+	}
+
+	interval := opts.HealthCheckInterval
+	if interval == 0 {
+		interval = 30 * time.Second
+	}
+
+	if interval > 0 {
+		p.wg.Add(1)
+		go p.healthLoop(interval)
+	}
+
+	return p, nil
+}
+
+// Close stops the health checks. Requests may still be made afterwards.
+func (p *Pool) Close() {
+	p.once.Do(func() {
+		close(p.stop)
+	})
+
+	p.wg.Wait()
+}
+
+// Status returns a snapshot of every host in the pool, in the order they were given.
+func (p *Pool) Status() []PoolHostStatus {
+	status := make([]PoolHostStatus, 0, len(p.hosts))
+	for _, h := range p.hosts {
+		h.mu.RLock()
+		loaded := make([]string, 0, len(h.loaded))
+		for name := range h.loaded {
+			loaded = append(loaded, name)
+		}
+		h.mu.RUnlock()
+
+		sort.Strings(loaded)
+		status = append(status, PoolHostStatus{
+			Client:   h.client,
+			Healthy:  h.healthy.Load(),
+			InFlight: h.inFlight.Load(),
+			Loaded:   loaded,
+		})
+	}
+
+	return status
+}
+
+// CheckHealth checks every host concurrently by calling Version and, for the model affinity
+// strategy, refreshes the models loaded on each healthy host with ProcessStatus.
+func (p *Pool) CheckHealth(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, h := range p.hosts {
+		wg.Add(1)
+
+		go func(h *poolHost) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, p.timeout)
+			defer cancel()
+
+			_, err := h.client.Version(ctx)
+			h.healthy.Store(err == nil)
+
+			if err != nil || p.strategy != PoolModelAffinity {
+				return
+			}
+
+			ps, err := h.client.ProcessStatus(ctx)
+			if err != nil {
+				return
+			}
+
+			loaded := make(map[string]bool, len(ps.Models))
+			for _, m := range ps.Models {
+				loaded[normalizeModelName(m.Name)] = true
+			}
+
+			h.mu.Lock()
+			h.loaded = loaded
+			h.mu.Unlock()
+		}(h)
+	}
+
+	wg.Wait()
+}
+
+// healthLoop runs CheckHealth immediately and then at every interval until Close is called.
+func (p *Pool) healthLoop(interval time.Duration) {
+	defer p.wg.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		<-p.stop
+		cancel()
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		p.CheckHealth(ctx)
+
+		select {
+		case <-p.stop:
+			return
+
+		case <-ticker.C:
+		}
+	}
+}
+
+// candidates returns the hosts to try for a request, in order: the healthy hosts ranked by
+// the strategy, followed by the unhealthy ones as a last resort.
+func (p *Pool) candidates(model string) []*poolHost {
+	n := len(p.hosts)
+	start := int((p.next.Add(1) - 1) % uint64(n))
+
+	var healthy, unhealthy []*poolHost
+	for i := 0; i < n; i++ {
+		h := p.hosts[(start+i)%n]
+		if h.healthy.Load() {
+			healthy = append(healthy, h)
+		} else {
+			unhealthy = append(unhealthy, h)
+		}
+	}
+
+	switch p.strategy {
+	case PoolLeastInFlight:
+		sort.SliceStable(healthy, func(i, j int) bool {
+			return healthy[i].inFlight.Load() < healthy[j].inFlight.Load()
+		})
+
+	case PoolModelAffinity:
+		want := normalizeModelName(model)
+		sort.SliceStable(healthy, func(i, j int) bool {
+			li, lj := healthy[i].isLoaded(want), healthy[j].isLoaded(want)
+			if li != lj {
+				return li
+			}
+
+			return healthy[i].inFlight.Load() < healthy[j].inFlight.Load()
+		})
+	}
+
+	return append(healthy, unhealthy...)
+}
+
+// failed records a request failure on h, marking the host down when the failure
+// happened at the transport level rather than being reported by the server.
+func (p *Pool) failed(h *poolHost, err error) {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		h.healthy.Store(false)
+	}
+}
+
+// isLoaded reports whether the model, already normalized with normalizeModelName, is loaded on h.
+func (h *poolHost) isLoaded(model string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.loaded[model]
+}
+
+// markLoaded records that a request for model succeeded on h, which leaves the model loaded.
+func (h *poolHost) markLoaded(model string) {
+	if model == "" {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.loaded[normalizeModelName(model)] = true
+}
+
+// shouldFailover is the default ShouldFailover classifier.
+func shouldFailover(err error) bool {
+	if errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) &&
+		apiErr.StatusCode >= 400 && apiErr.StatusCode < 500 {
+		return apiErr.StatusCode == http.StatusNotFound ||
+			apiErr.StatusCode == http.StatusTooManyRequests
+	}
+
+	return true
+}
+
+// poolDo runs fn against the pool's candidate hosts in order until one succeeds,
+// the error does not warrant a failover, or every host has been tried.
+func poolDo[T any](
+	ctx context.Context,
+	p *Pool,
+	model string,
+	fn func(*Client) (*T, error),
+) (*T, error) {
+	var lastErr error
+	for _, h := range p.candidates(model) {
+		h.inFlight.Add(1)
+		resp, err := fn(h.client)
+		h.inFlight.Add(-1)
+
+		if err == nil {
+			h.markLoaded(model)
+			return resp, nil
+		}
+
+		lastErr = err
+		if ctx.Err() != nil || !p.failover(err) {
+			return nil, err
+		}
+
+		p.failed(h, err)
+	}
+
+	return nil, lastErr
+}
+
+// poolStream yields the stream opened by fn on the first candidate host that does not fail
+// before its first chunk, failing over like poolDo until then.
+func poolStream[T any](
+	ctx context.Context,
+	p *Pool,
+	model string,
+	fn func(*Client) iter.Seq2[*T, error],
+) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		var lastErr error
+		for _, h := range p.candidates(model) {
+			started, failed := false, false
+
+			h.inFlight.Add(1)
+			for chunk, err := range fn(h.client) {
+				if err != nil && !started && ctx.Err() == nil && p.failover(err) {
+					p.failed(h, err)
+					lastErr, failed = err, true
+					break
+				}
+
+				started = true
+				if !yield(chunk, err) || err != nil {
+					h.inFlight.Add(-1)
+					return
+				}
+			}
+			h.inFlight.Add(-1)
+
+			if !failed {
+				h.markLoaded(model)
+				return
+			}
+		}
+
+		yield(nil, lastErr)
+	}
+}
+
+// Chat sends a chat request to a host chosen by the pool's strategy, failing over on errors.
+func (p *Pool) Chat(
+	ctx context.Context,
+	req *Chat,
+) (*ModelResponse, error) {
+	return poolDo(ctx, p, req.Model, func(c *Client) (*ModelResponse, error) {
+		return c.Chat(ctx, req)
+	})
+}
+
+// ChatStream streams a chat reply from a host chosen by the pool's strategy,
+// failing over on errors that occur before the first chunk.
+func (p *Pool) ChatStream(
+	ctx context.Context,
+	req *Chat,
+) iter.Seq2[*ModelResponse, error] {
+	return poolStream(ctx, p, req.Model, func(c *Client) iter.Seq2[*ModelResponse, error] {
+		return c.ChatStream(ctx, req)
+	})
+}
+
+// Generate sends a generation request to a host chosen by the pool's strategy, failing over on errors.
+func (p *Pool) Generate(
+	ctx context.Context,
+	req *PromptInfo,
+) (*PromptResult, error) {
+	return poolDo(ctx, p, req.Model, func(c *Client) (*PromptResult, error) {
+		return c.Generate(ctx, req)
+	})
+}
+
+// GenerateStream streams a generation from a host chosen by the pool's strategy,
+// failing over on errors that occur before the first chunk.
+func (p *Pool) GenerateStream(
+	ctx context.Context,
+	req *PromptInfo,
+) iter.Seq2[*PromptResult, error] {
+	return poolStream(ctx, p, req.Model, func(c *Client) iter.Seq2[*PromptResult, error] {
+		return c.GenerateStream(ctx, req)
+	})
+}
+
+// Embed generates an embedding on a host chosen by the pool's strategy, failing over on errors.
+func (p *Pool) Embed(
+	ctx context.Context,
+	model, input string,
+	options map[string]interface{},
+) (*EmbedResult, error) {
+	return poolDo(ctx, p, model, func(c *Client) (*EmbedResult, error) {
+		return c.Embed(ctx, model, input, options)
+	})
+}
+
+// EmbedBatch generates embeddings for many inputs on a host chosen by the pool's strategy,
+// failing over on errors. All batches of a call are sent to the same host.
+func (p *Pool) EmbedBatch(
+	ctx context.Context,
+	model string,
+	inputs []string,
+	opts *EmbedBatchOptions,
+) (*EmbedResult, error) {
+	return poolDo(ctx, p, model, func(c *Client) (*EmbedResult, error) {
+		return c.EmbedBatch(ctx, model, inputs, opts)
+	})
+}
@@ -0,0 +1,251 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloom_test
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/nthnn/golloom"
+	"github.com/nthnn/golloom/golloomtest"
+)
+
+// poolServers starts n fake servers and returns them with a pool over their clients.
+// Retries are disabled so that failures surface on the first attempt.
+func poolServers(
+	t *testing.T,
+	n int,
+	strategy golloom.PoolStrategy,
+) ([]*golloomtest.Server, *golloom.Pool) {
+	t.Helper()
+
+	servers := make([]*golloomtest.Server, n)
+	clients := make([]*golloom.Client, n)
+
+	for i := range servers {
+		servers[i] = golloomtest.NewServer()
+		t.Cleanup(servers[i].Close)
+
+		clients[i] = servers[i].Client(golloom.WithRetryPolicy(nil))
+	}
+
+	pool, err := golloom.NewPool(clients, &golloom.PoolOptions{
+		Strategy:            strategy,
+		HealthCheckInterval: -1,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(pool.Close)
+	return servers, pool
+}
+
+// poolChat sends a non-streamed chat for model through pool.
+func poolChat(pool *golloom.Pool, model string) (*golloom.ModelResponse, error) {
+	stream := false
+	return pool.Chat(context.Background(), &golloom.Chat{
+		Model:    model,
+		Messages: []golloom.Message{{Role: "user", Content: "hi"}},
+		Stream:   &stream,
+	})
+}
+
+// chatCounts returns the number of chat requests each server received.
+func chatCounts(servers []*golloomtest.Server) []int {
+	counts := make([]int, len(servers))
+	for i, srv := range servers {
+		counts[i] = len(srv.RequestsTo("/api/chat"))
+	}
+
+	return counts
+}
+
+func TestNewPoolWithoutClients(t *testing.T) {
+	if _, err := golloom.NewPool(nil, nil); err == nil {
+		t.Error("a pool without clients was created")
+	}
+}
+
+func TestPoolRoundRobin(t *testing.T) {
+	servers, pool := poolServers(t, 2, golloom.PoolRoundRobin)
+
+	for i := 0; i < 4; i++ {
+		if _, err := poolChat(pool, "llama3"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := chatCounts(servers); !reflect.DeepEqual(got, []int{2, 2}) {
+		t.Errorf("chat requests per host = %v, want [2 2]", got)
+	}
+}
+
+func TestPoolFailover(t *testing.T) {
+	servers, pool := poolServers(t, 2, golloom.PoolRoundRobin)
+	servers[0].Enqueue("/api/chat", golloomtest.ErrorResponse(http.StatusInternalServerError, "boom"))
+
+	resp, err := poolChat(pool, "llama3")
+	if err != nil || resp.Message.Content != "hi" {
+		t.Fatalf("reply = %+v, %v; want the second host's answer", resp, err)
+	}
+
+	if got := chatCounts(servers); !reflect.DeepEqual(got, []int{1, 1}) {
+		t.Errorf("chat requests per host = %v, want [1 1]", got)
+	}
+
+	if status := pool.Status(); !status[0].Healthy {
+		t.Error("a host answering with a server error was marked down")
+	}
+
+	for _, srv := range servers {
+		srv.Enqueue("/api/chat", golloomtest.ErrorResponse(http.StatusBadRequest, "invalid"))
+	}
+
+	if _, err := poolChat(pool, "llama3"); err == nil {
+		t.Error("a client error was not returned")
+	}
+
+	if got := chatCounts(servers); got[0]+got[1] != 3 {
+		t.Errorf("chat requests per host = %v; a client error failed over", got)
+	}
+}
+
+func TestPoolMarksUnreachableHostsDown(t *testing.T) {
+	servers, pool := poolServers(t, 2, golloom.PoolRoundRobin)
+	servers[0].Close()
+
+	for i := 0; i < 3; i++ {
+		if _, err := poolChat(pool, "llama3"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	status := pool.Status()
+	if status[0].Healthy || !status[1].Healthy {
+		t.Errorf("health = %v, %v; want the closed host down", status[0].Healthy, status[1].Healthy)
+	}
+
+	if got := len(servers[1].RequestsTo("/api/chat")); got != 3 {
+		t.Errorf("second host served %d chats, want 3", got)
+	}
+
+	var text string
+	for chunk, err := range pool.ChatStream(context.Background(), &golloom.Chat{
+		Model:    "llama3",
+		Messages: []golloom.Message{{Role: "user", Content: "streamed"}},
+	}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		text += chunk.Message.Content
+	}
+
+	if text != "streamed" {
+		t.Errorf("streamed reply = %q", text)
+	}
+
+	servers[1].Close()
+	pool.CheckHealth(context.Background())
+
+	if status := pool.Status(); status[1].Healthy {
+		t.Error("CheckHealth left a closed host healthy")
+	}
+
+	if _, err := poolChat(pool, "llama3"); err == nil {
+		t.Error("a chat succeeded with every host closed")
+	}
+}
+
+func TestPoolLeastInFlight(t *testing.T) {
+	servers, pool := poolServers(t, 2, golloom.PoolLeastInFlight)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	servers[0].Handle("/api/chat", func(*golloomtest.Request) golloomtest.Response {
+		close(started)
+		<-release
+
+		return golloomtest.ChatResponse("slow")
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := poolChat(pool, "llama3")
+		done <- err
+	}()
+
+	<-started
+
+	if status := pool.Status(); status[0].InFlight != 1 {
+		t.Errorf("in flight on the busy host = %d, want 1", status[0].InFlight)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := poolChat(pool, "llama3"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	if got := chatCounts(servers); !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Errorf("chat requests per host = %v, want [1 2]", got)
+	}
+}
+
+func TestPoolModelAffinity(t *testing.T) {
+	servers, pool := poolServers(t, 2, golloom.PoolModelAffinity)
+	servers[1].SetRunning(golloom.RunningModel{Name: "llama3:latest", Model: "llama3:latest"})
+
+	pool.CheckHealth(context.Background())
+
+	status := pool.Status()
+	if len(status[0].Loaded) != 0 || !reflect.DeepEqual(status[1].Loaded, []string{"llama3:latest"}) {
+		t.Fatalf("loaded = %v, %v; want llama3 on the second host", status[0].Loaded, status[1].Loaded)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := poolChat(pool, "llama3"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := chatCounts(servers); !reflect.DeepEqual(got, []int{0, 3}) {
+		t.Errorf("chat requests per host = %v, want [0 3]", got)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := poolChat(pool, "mistral"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := chatCounts(servers)
+	if got[0] != 3 && got[1] != 6 {
+		t.Errorf("chat requests per host = %v; mistral was not kept on the host that loaded it", got)
+	}
+}
@@ -20,14 +20,79 @@ package golloom
 import (
 	"context"
 	"encoding/json"
-	"net/http"
 	"net/url"
+	"time"
 )
 
+// RunningModel describes a model currently loaded into memory by the server,
+// including how it is split between VRAM and system RAM and when it will be unloaded.
+type RunningModel struct {
+	Name      string       `json:"name"`       // The name of the loaded model, e.g. "llama3:latest".
+	Model     string       `json:"model"`      // The model identifier, usually identical to Name.
+	Size      int64        `json:"size"`       // The total memory used by the model in bytes.
+	SizeVRAM  int64        `json:"size_vram"`  // The portion of Size held in GPU memory in bytes.
+	Digest    string       `json:"digest"`     // The digest of the loaded model.
+	Details   ModelDetails `json:"details"`    // Detailed attributes such as family and quantization level.
+	ExpiresAt time.Time    `json:"expires_at"` // The time at which the model will be unloaded if left idle.
+}
+
+// SizeRAM returns the portion of the model's memory held in system RAM rather than VRAM.
+func (m *RunningModel) SizeRAM() int64 {
+	return max(m.Size-m.SizeVRAM, 0)
+}
+
+// FullyOffloaded reports whether the whole model resides in GPU memory.
+func (m *RunningModel) FullyOffloaded() bool {
+	return m.Size > 0 && m.SizeVRAM >= m.Size
+}
+
 // ModelProcessStatus represents the JSON structure returned by the server,
-// containing a list of model names currently being processed.
+// containing the models currently loaded into memory.
 type ModelProcessStatus struct {
-	Models []string `json:"models"` // A slice of strings, each representing a model name.
+	Models []RunningModel `json:"models"` // The models currently loaded, one record per model.
+}
+
+// Find returns the loaded model matching name, comparing names by their canonical ModelRef form,
+// so "llama3" matches "library/llama3:latest".
+// The second result reports whether such a model is loaded.
+func (s *ModelProcessStatus) Find(name string) (*RunningModel, bool) {
+	want := normalizeModelName(name)
+	for i := range s.Models {
+		m := &s.Models[i]
+		if normalizeModelName(m.Name) == want || normalizeModelName(m.Model) == want {
+			return m, true
+		}
+	}
+
+	return nil, false
+}
+
+// IsLoaded reports whether the named model is currently loaded into memory.
+func (s *ModelProcessStatus) IsLoaded(name string) bool {
+	_, ok := s.Find(name)
+	return ok
+}
+
+// TimeUntilUnload returns how long the named model will stay loaded if left idle.
+// The second result is false when the model is not loaded, and a model already past
+// its expiry time reports zero.
+func (s *ModelProcessStatus) TimeUntilUnload(name string) (time.Duration, bool) {
+	m, ok := s.Find(name)
+	if !ok {
+		return 0, false
+	}
+
+	return max(time.Until(m.ExpiresAt), 0), true
+}
+
+// TotalVRAM returns the GPU memory in bytes used by all loaded models.
+func (s *ModelProcessStatus) TotalVRAM() int64 {
+	var total int64
+	for _, m := range s.Models {
+		total += m.SizeVRAM
+	}
+
+	return total
 }
 
 // ProcessStatus retrieves the current processing status of models from the server.
@@ -37,15 +102,15 @@ type ModelProcessStatus struct {
 //   - ctx: The context.Context for managing request deadlines and cancellations.
 //
 // Returns:
-//   - A pointer to a ModelProcessStatus containing the list of models being processed.
+//   - A pointer to a ModelProcessStatus containing the models currently loaded.
 //   - An error if the request fails or the response cannot be processed.
 func (c *Client) ProcessStatus(
 	ctx context.Context,
 ) (*ModelProcessStatus, error) {
 	rel := &url.URL{Path: "/api/ps"}
-	u := c.BaseURL.ResolveReference(rel)
+	u := c.resolve(rel)
 
-	req, err := http.NewRequestWithContext(
+	req, err := c.newRequest(
 		ctx,
 		"GET",
 		u.String(),
@@ -56,12 +121,16 @@ func (c *Client) ProcessStatus(
 		return nil, err
 	}
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.doBuffered(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+
 	var psResp ModelProcessStatus
 	err = json.NewDecoder(resp.Body).Decode(&psResp)
 	if err != nil {
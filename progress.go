@@ -0,0 +1,150 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloom
+
+import (
+	"context"
+	"time"
+)
+
+// ProgressEvent describes a single status update emitted while a model is pulled, pushed, created, or copied.
+// The raw fields mirror a line of the server's stream, while the aggregate fields summarize the progress
+// of every layer seen so far in the operation.
+type ProgressEvent struct {
+	Status    string `json:"status"`              // The status message reported by the server.
+	Digest    string `json:"digest,omitempty"`    // The digest of the layer being transferred, if any.
+	Total     int64  `json:"total,omitempty"`     // The total size in bytes of the layer being transferred.
+	Completed int64  `json:"completed,omitempty"` // The number of bytes of the layer transferred so far.
+
+	OverallTotal     int64         `json:"-"` // The combined size in bytes of every layer seen so far.
+	OverallCompleted int64         `json:"-"` // The combined number of bytes transferred across every layer.
+	Percent          float64       `json:"-"` // The overall completion percentage, from 0 to 100.
+	Elapsed          time.Duration `json:"-"` // The time elapsed since the operation started.
+	ETA              time.Duration `json:"-"` // The estimated time remaining, or zero when it cannot be estimated yet.
+}
+
+// ProgressFunc is a callback invoked for every ProgressEvent received from the server.
+// Returning a non-nil error aborts the operation and closes the underlying connection.
+type ProgressFunc func(*ProgressEvent) error
+
+// progressTracker accumulates per-layer byte counts to derive overall progress and ETA.
+type progressTracker struct {
+	start     time.Time
+	totals    map[string]int64
+	completed map[string]int64
+}
+
+// newProgressTracker creates a progressTracker whose clock starts immediately.
+func newProgressTracker() *progressTracker {
+	return &progressTracker{
+		start:     time.Now(),
+		totals:    make(map[string]int64),
+		completed: make(map[string]int64),
+	}
+}
+
+// observe records the layer counters carried by ev and fills in its aggregate fields.
+func (t *progressTracker) observe(ev *ProgressEvent) {
+	t.observeLayer(ev, ev.Digest)
+}
+
+// observeLayer is like observe, but files the counters of ev under key instead of its digest,
+// for transfers whose digest is not known yet. An empty key records no counters.
+func (t *progressTracker) observeLayer(ev *ProgressEvent, key string) {
+	if key != "" {
+		if ev.Total > 0 {
+			t.totals[key] = ev.Total
+		}
+
+		if ev.Completed > t.completed[key] {
+			t.completed[key] = ev.Completed
+		}
+	}
+
+	for _, total := range t.totals {
+		ev.OverallTotal += total
+	}
+
+	for _, completed := range t.completed {
+		ev.OverallCompleted += completed
+	}
+
+	ev.Elapsed = time.Since(t.start)
+	if ev.OverallTotal <= 0 {
+		return
+	}
+
+	ev.Percent = float64(ev.OverallCompleted) / float64(ev.OverallTotal) * 100
+	if ev.OverallCompleted > 0 && ev.OverallCompleted < ev.OverallTotal {
+		rate := float64(ev.OverallCompleted) / ev.Elapsed.Seconds()
+		remaining := float64(ev.OverallTotal - ev.OverallCompleted)
+
+		ev.ETA = time.Duration(remaining / rate * float64(time.Second))
+	}
+}
+
+// sendProgressStreamRequest sends a request whose response is a stream of progress lines,
+// passing each decoded ProgressEvent to fn as it arrives.
+// Parameters:
+//   - ctx: A context.Context for managing request deadlines and cancellations.
+//   - method: The HTTP method (e.g., "POST") to use for the request.
+//   - urlStr: The target URL as a string.
+//   - body: The payload to be sent with the request; it will be JSON-encoded.
+//   - fn: An optional callback receiving each event; a non-nil error aborts the stream.
+//
+// Returns:
+//   - The status messages received, one per line of the stream.
+//   - An error if the request fails, the server reports an error, or fn aborts the stream.
+func (c *Client) sendProgressStreamRequest(
+	ctx context.Context,
+	method, urlStr string,
+	body interface{},
+	fn ProgressFunc,
+) ([]string, error) {
+	resp, err := c.sendStreamRequest(
+		ctx,
+		method,
+		urlStr,
+		body,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var msgs []string
+	tracker := newProgressTracker()
+
+	for ev, err := range decodeStream[ProgressEvent](ctx, resp) {
+		if err != nil {
+			return msgs, err
+		}
+
+		tracker.observe(ev)
+		msgs = append(msgs, ev.Status)
+
+		if fn != nil {
+			if err := fn(ev); err != nil {
+				return msgs, err
+			}
+		}
+	}
+
+	return msgs, nil
+}
@@ -35,7 +35,7 @@ type PromptInfo struct {
 
 	Images  []string               `json:"images,omitempty"`  // List of images associated with the prompt; optional field.
 	Format  interface{}            `json:"format,omitempty"`  // Format of the prompt; can be string or map; optional field.
-	Options map[string]interface{} `json:"options,omitempty"` // Additional options for prompt customization; optional field.
+	Options map[string]interface{} `json:"options,omitempty"` // Additional options for prompt customization (see ModelOptions.ToMap); optional field.
 
 	Stream *bool `json:"stream,omitempty"` // Flag to indicate streaming response; optional field.
 	Raw    *bool `json:"raw,omitempty"`    // Flag to indicate raw response; optional field.
@@ -109,7 +109,7 @@ func (c *Client) Generate(
 	}
 
 	rel := &url.URL{Path: "/api/generate"}
-	u := c.BaseURL.ResolveReference(rel)
+	u := c.resolve(rel)
 
 	return c.sendRequest(ctx, "POST", u.String(), req)
 }
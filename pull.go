@@ -34,17 +34,28 @@ type PullModelResult struct {
 func (c *Client) PullModel(
 	ctx context.Context,
 	model string,
+) (*PullModelResult, error) {
+	return c.PullModelProgress(ctx, model, nil)
+}
+
+// PullModelProgress pulls a specified model like PullModel, reporting every progress
+// line to fn as a ProgressEvent carrying per-layer bytes, overall percentage, and ETA.
+func (c *Client) PullModelProgress(
+	ctx context.Context,
+	model string,
+	fn ProgressFunc,
 ) (*PullModelResult, error) {
 	rel := &url.URL{Path: "/api/pull"}
-	u := c.BaseURL.ResolveReference(rel)
+	u := c.resolve(rel)
 
-	res, err := c.sendStatusStreamRequest(
+	msgs, err := c.sendProgressStreamRequest(
 		ctx,
 		"POST",
 		u.String(),
 		map[string]string{
 			"model": model,
 		},
+		fn,
 	)
 
 	if err != nil {
@@ -52,6 +63,6 @@ func (c *Client) PullModel(
 	}
 
 	return &PullModelResult{
-		StatusMessages: res.StatusMessages,
+		StatusMessages: msgs,
 	}, nil
 }
@@ -34,17 +34,28 @@ type PushModelResult struct {
 func (c *Client) PushModel(
 	ctx context.Context,
 	model string,
+) (*PushModelResult, error) {
+	return c.PushModelProgress(ctx, model, nil)
+}
+
+// PushModelProgress pushes a specified model like PushModel, reporting every progress
+// line to fn as a ProgressEvent carrying per-layer bytes, overall percentage, and ETA.
+func (c *Client) PushModelProgress(
+	ctx context.Context,
+	model string,
+	fn ProgressFunc,
 ) (*PushModelResult, error) {
 	rel := &url.URL{Path: "/api/push"}
-	u := c.BaseURL.ResolveReference(rel)
+	u := c.resolve(rel)
 
-	res, err := c.sendStatusStreamRequest(
+	msgs, err := c.sendProgressStreamRequest(
 		ctx,
 		"POST",
 		u.String(),
 		map[string]interface{}{
 			"model": model,
 		},
+		fn,
 	)
 
 	if err != nil {
@@ -52,6 +63,6 @@ func (c *Client) PushModel(
 	}
 
 	return &PushModelResult{
-		StatusMessages: res.StatusMessages,
+		StatusMessages: msgs,
 	}, nil
 }
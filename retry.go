@@ -0,0 +1,240 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloom
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy describes how the Client retries requests that fail with a transient error,
+// such as a connection reset, a 502 or 503 from a proxy, or a server that is busy loading a model.
+// Requests are only retried before any part of the response body has been handed to the caller,
+// so streamed calls are never replayed once output has started arriving. Non-streamed calls such
+// as FetchModelInfo, Embed, ListModels, ProcessStatus, and Version read the whole body before
+// returning, so a connection reset while it is read is retried too.
+type RetryPolicy struct {
+	MaxAttempts    int           // The maximum number of attempts, including the first one; values below 2 disable retries.
+	InitialBackoff time.Duration // The delay before the first retry.
+	MaxBackoff     time.Duration // The upper bound for the delay between attempts.
+	Multiplier     float64       // The factor by which the delay grows after each attempt.
+	Jitter         float64       // The fraction, from 0 to 1, by which each delay is randomly varied.
+
+	// Retryable classifies whether an error should be retried. When nil, IsRetryable is used.
+	Retryable func(error) bool
+}
+
+// DefaultRetryPolicy returns a RetryPolicy with four attempts, exponential backoff starting
+// at 500 milliseconds and capped at 10 seconds, 20% jitter, and the IsRetryable classifier.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// WithRetryPolicy sets the retry policy applied to every request the client makes.
+// Passing nil disables retries.
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	return func(c *Client) error {
+		c.RetryPolicy = policy
+		return nil
+	}
+}
+
+// IsRetryable reports whether err is a transient failure worth retrying: a connection that was
+// refused, reset, or closed early, a timeout, or an APIError with status 429, 502, 503, or 504,
+// or one reporting that the server is busy or still loading a model. Context cancellation is
+// never retryable.
+func IsRetryable(err error) bool {
+	if err == nil ||
+		errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout:
+			return true
+		}
+
+		msg := strings.ToLower(apiErr.Message)
+		return strings.Contains(msg, "server busy") ||
+			strings.Contains(msg, "loading model")
+	}
+
+	if errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// backoff returns the delay to wait before the given retry, counting from 1.
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(p.InitialBackoff) * math.Pow(multiplier, float64(retry-1))
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		delay *= 1 + p.Jitter*(2*rand.Float64()-1)
+	}
+
+	return time.Duration(delay)
+}
+
+// retryable applies the policy's classifier to err.
+func (p *RetryPolicy) retryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+
+	return IsRetryable(err)
+}
+
+// do sends req through the client's HTTP client, retrying transient failures according to
+// the client's RetryPolicy. A request is only retried if its body can be replayed, and a
+// response is only retried before its body has been returned to the caller.
+// Parameters:
+//   - req: The request to send, typically built by newRequest or newJSONRequest.
+//
+// Returns:
+//   - A pointer to the http.Response of the last attempt; non-2xx responses are returned as is.
+//   - An error if the request could not be sent or the context was cancelled while waiting.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	return c.send(req, false)
+}
+
+// doBuffered is like do, but reads the whole body of a successful response before returning it,
+// so a connection reset while the body is read is retried as well. It suits non-streamed calls.
+func (c *Client) doBuffered(req *http.Request) (*http.Response, error) {
+	return c.send(req, true)
+}
+
+// send implements do and doBuffered.
+func (c *Client) send(
+	req *http.Request,
+	buffered bool,
+) (*http.Response, error) {
+	policy := c.RetryPolicy
+	if policy == nil || policy.MaxAttempts < 2 {
+		policy = &RetryPolicy{MaxAttempts: 1}
+	}
+
+	replayable := req.Body == nil ||
+		req.Body == http.NoBody ||
+		req.GetBody != nil
+
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+
+			req.Body = body
+		}
+
+		resp, err := c.HTTPClient.Do(req)
+		retryErr := err
+
+		switch {
+		case err != nil:
+
+		case resp.StatusCode < 200 || resp.StatusCode >= 300:
+			retryErr = peekAPIError(resp)
+
+		case buffered:
+			if err = readBody(resp); err != nil {
+				resp, retryErr = nil, err
+			}
+		}
+
+		if !replayable ||
+			attempt >= policy.MaxAttempts ||
+			!policy.retryable(retryErr) {
+			return resp, err
+		}
+
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(policy.backoff(attempt))
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+
+		case <-timer.C:
+		}
+	}
+}
+
+// readBody reads the whole body of resp into memory and replaces it with the buffered copy.
+func readBody(resp *http.Response) error {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if err != nil {
+		return err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return nil
+}
+
+// peekAPIError builds the APIError carried by a failed response while leaving
+// its body readable, so callers can still inspect the response themselves.
+func peekAPIError(resp *http.Response) *APIError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	resp.Body.Close()
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	apiErr := newAPIError(resp)
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return apiErr
+}
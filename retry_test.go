@@ -0,0 +1,292 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// fastRetryPolicy returns a RetryPolicy with the given number of attempts and negligible delays.
+func fastRetryPolicy(attempts int) *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    attempts,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+		Multiplier:     2,
+	}
+}
+
+// countingServer starts a server answering each request with handle, which receives the
+// 1-based number of the attempt, and returns it with a client retrying according to policy.
+func countingServer(
+	t *testing.T,
+	policy *RetryPolicy,
+	handle func(attempt int, w http.ResponseWriter, r *http.Request),
+) (*Client, func() int) {
+	t.Helper()
+
+	var mu sync.Mutex
+	attempts := 0
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		attempts++
+		attempt := attempts
+		mu.Unlock()
+
+		handle(attempt, w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := NewClientWithOptions(
+		WithBaseURL(srv.URL),
+		WithRetryPolicy(policy),
+	)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return client, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return attempts
+	}
+}
+
+func TestRetryTransientStatus(t *testing.T) {
+	client, attempts := countingServer(t, fastRetryPolicy(4), func(attempt int, w http.ResponseWriter, _ *http.Request) {
+		if attempt < 3 {
+			http.Error(w, `{"error":"service unavailable"}`, http.StatusServiceUnavailable)
+			return
+		}
+
+		fmt.Fprint(w, `{"version":"0.5.0"}`)
+	})
+
+	v, err := client.Version(context.Background())
+	if err != nil {
+		t.Fatalf("Version: %v", err)
+	}
+
+	if v.Version != "0.5.0" {
+		t.Errorf("version = %q, want 0.5.0", v.Version)
+	}
+
+	if got := attempts(); got != 3 {
+		t.Errorf("attempts = %d, want 3", got)
+	}
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	client, attempts := countingServer(t, fastRetryPolicy(3), func(_ int, w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, `{"error":"server busy"}`, http.StatusServiceUnavailable)
+	})
+
+	_, err := client.Version(context.Background())
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("err = %v, want a 503 APIError", err)
+	}
+
+	if apiErr.Message != "server busy" {
+		t.Errorf("message = %q, want the server's message", apiErr.Message)
+	}
+
+	if got := attempts(); got != 3 {
+		t.Errorf("attempts = %d, want 3", got)
+	}
+}
+
+func TestRetrySkipsPermanentErrors(t *testing.T) {
+	client, attempts := countingServer(t, fastRetryPolicy(4), func(_ int, w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, `{"error":"model 'x' not found"}`, http.StatusNotFound)
+	})
+
+	_, err := client.FetchModelInfo(context.Background(), "x", false)
+	if !IsModelNotFound(err) {
+		t.Fatalf("err = %v, want a model-not-found error", err)
+	}
+
+	if got := attempts(); got != 1 {
+		t.Errorf("attempts = %d, want 1", got)
+	}
+}
+
+func TestRetryDisabled(t *testing.T) {
+	client, attempts := countingServer(t, nil, func(_ int, w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "bad gateway", http.StatusBadGateway)
+	})
+
+	if _, err := client.Version(context.Background()); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if got := attempts(); got != 1 {
+		t.Errorf("attempts = %d, want 1", got)
+	}
+}
+
+func TestRetryReplaysRequestBody(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+
+	client, _ := countingServer(t, fastRetryPolicy(2), func(attempt int, w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		mu.Unlock()
+
+		if attempt == 1 {
+			http.Error(w, "bad gateway", http.StatusBadGateway)
+			return
+		}
+
+		fmt.Fprint(w, `{"model":"m","embeddings":[[1,2]]}`)
+	})
+
+	if _, err := client.Embed(context.Background(), "m", "hello", nil); err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+
+	if len(bodies) != 2 || bodies[0] == "" || bodies[0] != bodies[1] {
+		t.Errorf("request bodies = %q, want the same body twice", bodies)
+	}
+}
+
+func TestRetryConnectionResetWhileReadingBody(t *testing.T) {
+	client, attempts := countingServer(t, fastRetryPolicy(3), func(attempt int, w http.ResponseWriter, _ *http.Request) {
+		if attempt == 1 {
+			conn, buf, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			buf.WriteString("HTTP/1.1 200 OK\r\nContent-Type: application/json\r\nContent-Length: 100\r\n\r\n{\"models\":")
+			buf.Flush()
+			conn.Close()
+
+			return
+		}
+
+		fmt.Fprint(w, `{"models":[{"name":"llama3:latest"}]}`)
+	})
+
+	list, err := client.ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("ListModels: %v", err)
+	}
+
+	if len(list.Models) != 1 || list.Models[0].Name != "llama3:latest" {
+		t.Errorf("models = %+v, want llama3:latest", list.Models)
+	}
+
+	if got := attempts(); got != 2 {
+		t.Errorf("attempts = %d, want 2", got)
+	}
+}
+
+func TestRetryStopsWhenContextIsCancelled(t *testing.T) {
+	policy := fastRetryPolicy(5)
+	policy.InitialBackoff = time.Hour
+	policy.MaxBackoff = time.Hour
+
+	client, attempts := countingServer(t, policy, func(_ int, w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.Version(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+
+	if got := attempts(); got != 1 {
+		t.Errorf("attempts = %d, want 1", got)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"canceled", context.Canceled, false},
+		{"deadline", fmt.Errorf("wrapped: %w", context.DeadlineExceeded), false},
+		{"reset", fmt.Errorf("read: %w", syscall.ECONNRESET), true},
+		{"refused", syscall.ECONNREFUSED, true},
+		{"unexpected eof", io.ErrUnexpectedEOF, true},
+		{"429", &APIError{StatusCode: http.StatusTooManyRequests}, true},
+		{"504", &APIError{StatusCode: http.StatusGatewayTimeout}, true},
+		{"loading", &APIError{StatusCode: http.StatusInternalServerError, Message: "Loading model"}, true},
+		{"400", &APIError{StatusCode: http.StatusBadRequest, Message: "invalid"}, false},
+		{"other", errors.New("boom"), false},
+	}
+
+	for _, tt := range tests {
+		if got := IsRetryable(tt.err); got != tt.want {
+			t.Errorf("IsRetryable(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := &RetryPolicy{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		Multiplier:     2,
+	}
+
+	want := []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+	}
+
+	for i, w := range want {
+		if got := policy.backoff(i + 1); got != w {
+			t.Errorf("backoff(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := policy.backoff(1); got < 50*time.Millisecond || got > 150*time.Millisecond {
+			t.Fatalf("jittered backoff %v outside [50ms, 150ms]", got)
+		}
+	}
+}
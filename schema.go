@@ -0,0 +1,280 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloom
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// SchemaOf derives a JSON schema from the Go type T, suitable for Chat.Format,
+// PromptInfo.Format, or ToolFunction.Parameters. See SchemaFor for the supported tags.
+func SchemaOf[T any]() (map[string]interface{}, error) {
+	return schemaForType(reflect.TypeFor[T]())
+}
+
+// SchemaFor derives a JSON schema from the type of v, which is typically a struct value or pointer.
+// Property names follow the json struct tags, and fields marked "omitempty" or held in pointers are
+// optional while all others are required. The "description" tag documents a property and the "enum"
+// tag restricts it to a comma-separated list of values. Nested structs, slices, arrays, maps with
+// string, integer, or encoding.TextMarshaler keys, pointers, and time.Time are supported. As with
+// encoding/json, byte slices are described as strings and byte arrays as arrays of integers.
+// Parameters:
+//   - v: A value whose type describes the schema.
+//
+// Returns:
+//   - The JSON schema as a map.
+//   - An error if the type contains unsupported kinds such as channels or functions, or is recursive.
+func SchemaFor(v interface{}) (map[string]interface{}, error) {
+	if v == nil {
+		return nil, fmt.Errorf("cannot derive a schema from nil")
+	}
+
+	return schemaForType(reflect.TypeOf(v))
+}
+
+// schemaForType derives the schema of t, tracking the struct types being visited to detect recursion.
+func schemaForType(t reflect.Type) (map[string]interface{}, error) {
+	return (&schemaBuilder{visiting: make(map[reflect.Type]bool)}).build(t)
+}
+
+// schemaBuilder walks Go types to produce JSON schemas.
+type schemaBuilder struct {
+	visiting map[reflect.Type]bool
+}
+
+// build returns the schema describing values of type t.
+func (b *schemaBuilder) build(t reflect.Type) (map[string]interface{}, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}, nil
+
+	case t == rawMessageType:
+		return map[string]interface{}{}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}, nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}, nil
+
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}, nil
+
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}, nil
+
+	case reflect.Interface:
+		return map[string]interface{}{}, nil
+
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string"}, nil
+		}
+
+		items, err := b.build(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]interface{}{"type": "array", "items": items}, nil
+
+	case reflect.Map:
+		if !isMapKeyType(t.Key()) {
+			return nil, fmt.Errorf("unsupported map key type %s", t.Key())
+		}
+
+		values, err := b.build(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]interface{}{"type": "object", "additionalProperties": values}, nil
+
+	case reflect.Struct:
+		return b.buildStruct(t)
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
+
+// isMapKeyType reports whether encoding/json can encode map keys of type t as object keys:
+// strings, integers, and types implementing encoding.TextMarshaler.
+func isMapKeyType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+
+	return t.Implements(textMarshalerType)
+}
+
+// buildStruct returns the object schema describing the exported fields of the struct type t.
+func (b *schemaBuilder) buildStruct(t reflect.Type) (map[string]interface{}, error) {
+	if b.visiting[t] {
+		return nil, fmt.Errorf("recursive type %s is not supported", t)
+	}
+
+	b.visiting[t] = true
+	defer delete(b.visiting, t)
+
+	properties := make(map[string]interface{})
+	required := []string{}
+
+	if err := b.collectFields(t, properties, &required); err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}, nil
+}
+
+// collectFields adds the properties of t's fields to properties, flattening embedded structs
+// the same way encoding/json does.
+func (b *schemaBuilder) collectFields(
+	t reflect.Type,
+	properties map[string]interface{},
+	required *[]string,
+) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				if err := b.collectFields(embedded, properties, required); err != nil {
+					return err
+				}
+
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		prop, err := b.build(field.Type)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+
+		if desc := field.Tag.Get("description"); desc != "" {
+			prop["description"] = desc
+		}
+
+		if enum := field.Tag.Get("enum"); enum != "" {
+			values := []interface{}{}
+			for _, v := range strings.Split(enum, ",") {
+				value, err := enumValue(field.Type, strings.TrimSpace(v))
+				if err != nil {
+					return fmt.Errorf("field %s: %w", field.Name, err)
+				}
+
+				values = append(values, value)
+			}
+
+			prop["enum"] = values
+		}
+
+		properties[name] = prop
+		optional := field.Type.Kind() == reflect.Pointer ||
+			strings.Contains(","+opts+",", ",omitempty,") ||
+			strings.Contains(","+opts+",", ",omitzero,")
+
+		if !optional {
+			*required = append(*required, name)
+		}
+	}
+
+	return nil
+}
+
+// enumValue converts an entry of an "enum" tag to the JSON type of the field it annotates.
+func enumValue(t reflect.Type, s string) (interface{}, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseInt(s, 10, 64)
+
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(s, 64)
+
+	case reflect.Bool:
+		return strconv.ParseBool(s)
+	}
+
+	return s, nil
+}
+
+// ToolFunctionFor builds a ToolFunction whose parameters schema is derived from the Go type T.
+// Handlers can decode the model's arguments back into T with ToolCallFunction.ArgumentsInto.
+func ToolFunctionFor[T any](name, description string) (ToolFunction, error) {
+	params, err := SchemaOf[T]()
+	if err != nil {
+		return ToolFunction{}, err
+	}
+
+	return ToolFunction{
+		Name:        name,
+		Description: description,
+		Parameters:  params,
+	}, nil
+}
@@ -0,0 +1,184 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloom
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// schemaJSON derives the schema of T and returns it as compact JSON.
+func schemaJSON[T any](t *testing.T) string {
+	t.Helper()
+
+	schema, err := SchemaOf[T]()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+type schemaBase struct {
+	ID int `json:"id"`
+}
+
+type schemaPoint struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+type schemaKey string
+
+func (k schemaKey) MarshalText() ([]byte, error) {
+	return []byte(strings.ToUpper(string(k))), nil
+}
+
+func TestSchemaOfStruct(t *testing.T) {
+	type weather struct {
+		schemaBase
+		City     string        `json:"city" description:"The city name."`
+		Unit     string        `json:"unit,omitempty" enum:"celsius, fahrenheit"`
+		Days     *int          `json:"days"`
+		Level    int           `json:"level" enum:"1,2,3"`
+		At       time.Time     `json:"at"`
+		Points   []schemaPoint `json:"points"`
+		Ignored  string        `json:"-"`
+		internal string
+	}
+
+	got := schemaJSON[weather](t)
+	want := `{"properties":{` +
+		`"at":{"format":"date-time","type":"string"},` +
+		`"city":{"description":"The city name.","type":"string"},` +
+		`"days":{"type":"integer"},` +
+		`"id":{"type":"integer"},` +
+		`"level":{"enum":[1,2,3],"type":"integer"},` +
+		`"points":{"items":{"properties":{"x":{"type":"number"},"y":{"type":"number"}},"required":["x","y"],"type":"object"},"type":"array"},` +
+		`"unit":{"enum":["celsius","fahrenheit"],"type":"string"}},` +
+		`"required":["id","city","level","at","points"],"type":"object"}`
+
+	if got != want {
+		t.Errorf("schema =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSchemaOfBytes(t *testing.T) {
+	type blobs struct {
+		Slice []byte  `json:"slice"`
+		Array [2]byte `json:"array"`
+	}
+
+	schema, err := SchemaOf[blobs]()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	props := schema["properties"].(map[string]interface{})
+	if got := props["slice"]; !reflect.DeepEqual(got, map[string]interface{}{"type": "string"}) {
+		t.Errorf("[]byte schema = %v, want a string", got)
+	}
+
+	wantArray := map[string]interface{}{
+		"type":  "array",
+		"items": map[string]interface{}{"type": "integer"},
+	}
+
+	if got := props["array"]; !reflect.DeepEqual(got, wantArray) {
+		t.Errorf("[2]byte schema = %v, want an array of integers", got)
+	}
+
+	data, _ := json.Marshal(blobs{Array: [2]byte{1, 2}})
+	if !strings.Contains(string(data), `"array":[1,2]`) {
+		t.Fatalf("encoding/json wrote %s; the schema no longer matches it", data)
+	}
+}
+
+func TestSchemaOfMapKeys(t *testing.T) {
+	want := `{"additionalProperties":{"type":"string"},"type":"object"}`
+
+	if got := schemaJSON[map[string]string](t); got != want {
+		t.Errorf("map[string]string schema = %s", got)
+	}
+
+	if got := schemaJSON[map[int]string](t); got != want {
+		t.Errorf("map[int]string schema = %s", got)
+	}
+
+	if got := schemaJSON[map[uint8]string](t); got != want {
+		t.Errorf("map[uint8]string schema = %s", got)
+	}
+
+	if got := schemaJSON[map[schemaKey]string](t); got != want {
+		t.Errorf("map[schemaKey]string schema = %s", got)
+	}
+
+	if _, err := SchemaOf[map[schemaPoint]string](); err == nil {
+		t.Error("map with struct keys was accepted")
+	}
+}
+
+func TestSchemaOfUnsupported(t *testing.T) {
+	type recursive struct {
+		Next *recursive `json:"next"`
+	}
+
+	type withChan struct {
+		C chan int `json:"c"`
+	}
+
+	if _, err := SchemaOf[recursive](); err == nil {
+		t.Error("recursive type was accepted")
+	}
+
+	if _, err := SchemaOf[withChan](); err == nil {
+		t.Error("channel field was accepted")
+	}
+
+	if _, err := SchemaFor(nil); err == nil {
+		t.Error("nil value was accepted")
+	}
+}
+
+func TestToolFunctionFor(t *testing.T) {
+	type args struct {
+		Query string `json:"query"`
+	}
+
+	fn, err := ToolFunctionFor[args]("search", "Searches the web.")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fn.Name != "search" || fn.Description != "Searches the web." {
+		t.Errorf("tool function = %+v", fn)
+	}
+
+	params := fn.Parameters.(map[string]interface{})
+	if !reflect.DeepEqual(params["required"], []string{"query"}) {
+		t.Errorf("required = %v, want [query]", params["required"])
+	}
+}
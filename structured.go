@@ -0,0 +1,193 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloom
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Validator is implemented by structured-output types that check their own invariants.
+// GenerateInto and ChatInto call Validate after decoding, treating a failure like a decoding error.
+type Validator interface {
+	Validate() error
+}
+
+// GenerateInto sends a generation request whose Format is set to the JSON schema of T and decodes
+// the response into a T. When the response does not decode or validate, the model is prompted again
+// with its previous answer and the error, up to maxRepairs times. The caller's PromptInfo is left untouched.
+// Parameters:
+//   - ctx: A context for controlling cancellation and timeouts for every request.
+//   - c: The Generator used to send the requests, typically a *Client.
+//   - req: A pointer to a PromptInfo struct describing the generation request.
+//   - maxRepairs: The number of repair attempts made after the first response; 0 disables repairs.
+//
+// Returns:
+//   - A pointer to the decoded and validated T.
+//   - An error if a request fails or no response could be decoded within the allowed repairs.
+func GenerateInto[T any](
+	ctx context.Context,
+	c Generator,
+	req *PromptInfo,
+	maxRepairs int,
+) (*T, error) {
+	schema, err := SchemaOf[T]()
+	if err != nil {
+		return nil, err
+	}
+
+	genReq := *req
+	genReq.Format = schema
+
+	var lastErr error
+	for attempt := 0; attempt <= maxRepairs; attempt++ {
+		resp, err := c.Generate(ctx, &genReq)
+		if err != nil {
+			return nil, err
+		}
+
+		out, err := decodeStructured[T](resp.Response, schema)
+		if err == nil {
+			return out, nil
+		}
+
+		lastErr = err
+		genReq.Prompt = repairPrompt(req.Prompt, resp.Response, err)
+	}
+
+	return nil, fmt.Errorf(
+		"structured output did not validate after %d repairs: %w",
+		maxRepairs,
+		lastErr,
+	)
+}
+
+// ChatInto sends a chat request whose Format is set to the JSON schema of T and decodes the reply
+// into a T. When the reply does not decode or validate, the invalid reply and a message describing
+// the error are added to the conversation and the model is asked again, up to maxRepairs times.
+// The caller's Chat and its Messages are left untouched.
+// Parameters:
+//   - ctx: A context for controlling cancellation and timeouts for every request.
+//   - c: The Chatter used to send the requests, typically a *Client.
+//   - req: A pointer to a Chat struct containing the conversation history and optional configuration.
+//   - maxRepairs: The number of repair attempts made after the first reply; 0 disables repairs.
+//
+// Returns:
+//   - A pointer to the decoded and validated T.
+//   - An error if a request fails or no reply could be decoded within the allowed repairs.
+func ChatInto[T any](
+	ctx context.Context,
+	c Chatter,
+	req *Chat,
+	maxRepairs int,
+) (*T, error) {
+	schema, err := SchemaOf[T]()
+	if err != nil {
+		return nil, err
+	}
+
+	chatReq := *req
+	chatReq.Format = schema
+	chatReq.Messages = append([]Message(nil), req.Messages...)
+
+	var lastErr error
+	for attempt := 0; attempt <= maxRepairs; attempt++ {
+		resp, err := c.Chat(ctx, &chatReq)
+		if err != nil {
+			return nil, err
+		}
+
+		out, err := decodeStructured[T](resp.Message.Content, schema)
+		if err == nil {
+			return out, nil
+		}
+
+		lastErr = err
+		chatReq.Messages = append(
+			chatReq.Messages,
+			resp.Message,
+			Message{
+				Role: "user",
+				Content: fmt.Sprintf(
+					"Your reply was not valid: %v. Respond again with only JSON that matches the requested schema.",
+					err,
+				),
+			},
+		)
+	}
+
+	return nil, fmt.Errorf(
+		"structured output did not validate after %d repairs: %w",
+		maxRepairs,
+		lastErr,
+	)
+}
+
+// decodeStructured decodes text into a T, rejecting unknown fields and missing required
+// properties of the schema, and then runs T's Validate method if it implements Validator.
+func decodeStructured[T any](
+	text string,
+	schema map[string]interface{},
+) (*T, error) {
+	data := []byte(strings.TrimSpace(text))
+	if required, ok := schema["required"].([]string); ok && len(required) > 0 {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+
+		for _, name := range required {
+			if _, ok := fields[name]; !ok {
+				return nil, fmt.Errorf("missing required field %q", name)
+			}
+		}
+	}
+
+	out := new(T)
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(out); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	if v, ok := any(out).(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return nil, fmt.Errorf("validation failed: %w", err)
+		}
+	}
+
+	return out, nil
+}
+
+// repairPrompt builds the prompt used to ask the model to correct an invalid structured response.
+func repairPrompt(
+	prompt, previous string,
+	err error,
+) string {
+	return fmt.Sprintf(
+		"%s\n\nA previous answer to this prompt was:\n%s\n\nThat answer was not valid: %v. "+
+			"Respond again with only JSON that matches the requested schema.",
+		prompt,
+		previous,
+		err,
+	)
+}
@@ -0,0 +1,220 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloom
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrToolStepLimit is returned by RunWithTools when the model keeps requesting tools
+// after the configured number of steps.
+var ErrToolStepLimit = errors.New("tool step limit reached before the model answered")
+
+// Tool describes a tool the model may call during a chat, in the format expected by the server.
+type Tool struct {
+	Type     string       `json:"type"`     // The kind of tool; currently always "function".
+	Function ToolFunction `json:"function"` // The function definition exposed to the model.
+}
+
+// ToolFunction defines a function the model may call: its name, a description
+// of what it does, and a JSON schema describing its arguments.
+type ToolFunction struct {
+	Name        string      `json:"name"`                  // The name the model uses to call the function.
+	Description string      `json:"description,omitempty"` // A description of what the function does; optional field.
+	Parameters  interface{} `json:"parameters,omitempty"`  // A JSON schema object describing the arguments; optional field.
+}
+
+// ToolCall is a request from the model to invoke one of the tools offered in Chat.Tools.
+type ToolCall struct {
+	Function ToolCallFunction `json:"function"` // The function invocation requested by the model.
+}
+
+// ToolCallFunction holds the name and decoded arguments of a requested function invocation.
+type ToolCallFunction struct {
+	Index     int                    `json:"index,omitempty"` // The position of the call within the message; optional field.
+	Name      string                 `json:"name"`            // The name of the function to invoke.
+	Arguments map[string]interface{} `json:"arguments"`       // The arguments supplied by the model.
+}
+
+// ToolHandler executes a tool call and returns the text sent back to the model as the tool's result.
+type ToolHandler func(ctx context.Context, args map[string]interface{}) (string, error)
+
+// ToolRegistry maps tool names to their definitions and Go handlers.
+// It is safe for concurrent use.
+type ToolRegistry struct {
+	mu       sync.RWMutex
+	order    []string
+	tools    map[string]ToolFunction
+	handlers map[string]ToolHandler
+}
+
+// NewToolRegistry creates an empty ToolRegistry.
+func NewToolRegistry() *ToolRegistry {
+	return &ToolRegistry{
+		tools:    make(map[string]ToolFunction),
+		handlers: make(map[string]ToolHandler),
+	}
+}
+
+// Register adds a tool and its handler to the registry.
+// Parameters:
+//   - fn: The function definition exposed to the model; its Name must be unique.
+//   - handler: The Go function executed when the model calls the tool.
+//
+// Returns:
+//   - An error if the name is empty, the handler is nil, or the name is already registered.
+func (r *ToolRegistry) Register(
+	fn ToolFunction,
+	handler ToolHandler,
+) error {
+	if fn.Name == "" {
+		return fmt.Errorf("tool name must not be empty")
+	}
+
+	if handler == nil {
+		return fmt.Errorf("tool %q has no handler", fn.Name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.tools[fn.Name]; exists {
+		return fmt.Errorf("tool %q is already registered", fn.Name)
+	}
+
+	r.order = append(r.order, fn.Name)
+	r.tools[fn.Name] = fn
+	r.handlers[fn.Name] = handler
+
+	return nil
+}
+
+// Tools returns the registered tools in registration order, ready to be set as Chat.Tools.
+func (r *ToolRegistry) Tools() []Tool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tools := make([]Tool, 0, len(r.order))
+	for _, name := range r.order {
+		tools = append(tools, Tool{
+			Type:     "function",
+			Function: r.tools[name],
+		})
+	}
+
+	return tools
+}
+
+// Call executes the handler registered for the requested tool.
+// Parameters:
+//   - ctx: A context passed through to the handler.
+//   - call: The tool call requested by the model.
+//
+// Returns:
+//   - The text result produced by the handler.
+//   - An error if the tool is unknown or the handler fails.
+func (r *ToolRegistry) Call(
+	ctx context.Context,
+	call ToolCall,
+) (string, error) {
+	r.mu.RLock()
+	handler, ok := r.handlers[call.Function.Name]
+	r.mu.RUnlock()
+
+	if !ok {
+		return "", fmt.Errorf("unknown tool %q", call.Function.Name)
+	}
+
+	return handler(ctx, call.Function.Arguments)
+}
+
+// RunWithTools runs a chat in which the model may call tools from the registry.
+// Each time the model requests tools, the assistant message and one "tool" message per result
+// are appended to req.Messages and the chat is sent again, until the model answers without
+// requesting tools. The final answer is appended as well, so req.Messages holds the whole
+// exchange afterwards. Handler failures and unknown tools are reported to the model as the
+// tool's result so it can recover. If req.Tools is empty, the registry's tools are offered.
+// Parameters:
+//   - ctx: A context for controlling cancellation and timeouts for every request.
+//   - req: A pointer to a Chat struct containing the conversation; its Messages are extended in place.
+//   - registry: The ToolRegistry providing tool definitions and handlers.
+//   - maxSteps: The maximum number of chat requests to send; values below 1 default to 8.
+//
+// Returns:
+//   - A pointer to the ModelResponse carrying the model's final answer.
+//   - ErrToolStepLimit if the model still requested tools after maxSteps requests, or any request error.
+func (c *Client) RunWithTools(
+	ctx context.Context,
+	req *Chat,
+	registry *ToolRegistry,
+	maxSteps int,
+) (*ModelResponse, error) {
+	if maxSteps < 1 {
+		maxSteps = 8
+	}
+
+	if len(req.Tools) == 0 {
+		req.Tools = registry.Tools()
+	}
+
+	for step := 0; step < maxSteps; step++ {
+		resp, err := c.Chat(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Messages = append(req.Messages, resp.Message)
+		if len(resp.Message.ToolCalls) == 0 {
+			return resp, nil
+		}
+
+		for _, call := range resp.Message.ToolCalls {
+			result, err := registry.Call(ctx, call)
+			if err != nil {
+				if ctxErr := ctx.Err(); ctxErr != nil {
+					return nil, ctxErr
+				}
+
+				result = "error: " + err.Error()
+			}
+
+			req.Messages = append(req.Messages, Message{
+				Role:     "tool",
+				Content:  result,
+				ToolName: call.Function.Name,
+			})
+		}
+	}
+
+	return nil, ErrToolStepLimit
+}
+
+// ArgumentsInto decodes the arguments of a tool call into v, which should be a pointer
+// to a struct whose json tags match the tool's parameter names.
+func (f *ToolCallFunction) ArgumentsInto(v interface{}) error {
+	data, err := json.Marshal(f.Arguments)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
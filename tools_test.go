@@ -0,0 +1,216 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloom_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/nthnn/golloom"
+	"github.com/nthnn/golloom/golloomtest"
+)
+
+// addTool registers an "add" tool summing its integer arguments a and b.
+func addTool(t *testing.T, registry *golloom.ToolRegistry) {
+	t.Helper()
+
+	err := registry.Register(
+		golloom.ToolFunction{Name: "add", Description: "Adds two numbers."},
+		func(_ context.Context, args map[string]interface{}) (string, error) {
+			var in struct {
+				A int `json:"a"`
+				B int `json:"b"`
+			}
+
+			call := golloom.ToolCallFunction{Arguments: args}
+			if err := call.ArgumentsInto(&in); err != nil {
+				return "", err
+			}
+
+			return fmt.Sprint(in.A + in.B), nil
+		},
+	)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// toolCall builds a call of the named tool with the given arguments.
+func toolCall(name string, args map[string]interface{}) golloom.ToolCall {
+	return golloom.ToolCall{
+		Function: golloom.ToolCallFunction{
+			Name:      name,
+			Arguments: args,
+		},
+	}
+}
+
+func TestToolRegistryRegister(t *testing.T) {
+	registry := golloom.NewToolRegistry()
+	addTool(t, registry)
+
+	noop := func(context.Context, map[string]interface{}) (string, error) {
+		return "", nil
+	}
+
+	if err := registry.Register(golloom.ToolFunction{Name: "add"}, noop); err == nil {
+		t.Error("registering a duplicate name succeeded")
+	}
+
+	if err := registry.Register(golloom.ToolFunction{}, noop); err == nil {
+		t.Error("registering an empty name succeeded")
+	}
+
+	if err := registry.Register(golloom.ToolFunction{Name: "nil"}, nil); err == nil {
+		t.Error("registering a nil handler succeeded")
+	}
+
+	if err := registry.Register(golloom.ToolFunction{Name: "second"}, noop); err != nil {
+		t.Fatal(err)
+	}
+
+	tools := registry.Tools()
+	if len(tools) != 2 || tools[0].Function.Name != "add" || tools[1].Function.Name != "second" {
+		t.Fatalf("tools = %+v, want add and second in registration order", tools)
+	}
+
+	if tools[0].Type != "function" {
+		t.Errorf("type = %q, want function", tools[0].Type)
+	}
+
+	if _, err := registry.Call(context.Background(), toolCall("missing", nil)); err == nil {
+		t.Error("calling an unknown tool succeeded")
+	}
+}
+
+func TestRunWithTools(t *testing.T) {
+	srv := golloomtest.NewServer()
+	defer srv.Close()
+
+	srv.Enqueue(
+		"/api/chat",
+		golloomtest.ToolCallResponse(
+			toolCall("add", map[string]interface{}{"a": 2, "b": 3}),
+			toolCall("missing", nil),
+		),
+		golloomtest.ChatResponse("The sum is 5."),
+	)
+
+	registry := golloom.NewToolRegistry()
+	addTool(t, registry)
+
+	req := &golloom.Chat{
+		Model:    "llama3",
+		Messages: []golloom.Message{{Role: "user", Content: "What is 2 + 3?"}},
+	}
+
+	resp, err := srv.Client().RunWithTools(context.Background(), req, registry, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Message.Content != "The sum is 5." {
+		t.Errorf("answer = %q", resp.Message.Content)
+	}
+
+	roles := make([]string, len(req.Messages))
+	for i, msg := range req.Messages {
+		roles[i] = msg.Role
+	}
+
+	if fmt.Sprint(roles) != "[user assistant tool tool assistant]" {
+		t.Fatalf("roles = %v", roles)
+	}
+
+	if got := req.Messages[2]; got.Content != "5" || got.ToolName != "add" {
+		t.Errorf("tool result = %+v, want 5 from add", got)
+	}
+
+	if got := req.Messages[3].Content; got != `error: unknown tool "missing"` {
+		t.Errorf("unknown tool result = %q", got)
+	}
+
+	chats := srv.RequestsTo("/api/chat")
+	if len(chats) != 2 {
+		t.Fatalf("chat requests = %d, want 2", len(chats))
+	}
+
+	var sent golloom.Chat
+	if err := chats[1].Decode(&sent); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sent.Tools) != 1 || sent.Tools[0].Function.Name != "add" {
+		t.Errorf("offered tools = %+v, want the registry's", sent.Tools)
+	}
+
+	if len(sent.Messages) != 4 {
+		t.Errorf("second request carried %d messages, want 4", len(sent.Messages))
+	}
+}
+
+func TestRunWithToolsStepLimit(t *testing.T) {
+	srv := golloomtest.NewServer()
+	defer srv.Close()
+
+	call := golloomtest.ToolCallResponse(toolCall("add", map[string]interface{}{"a": 1, "b": 1}))
+	srv.Enqueue("/api/chat", call, call, call)
+
+	registry := golloom.NewToolRegistry()
+	addTool(t, registry)
+
+	req := &golloom.Chat{
+		Model:    "llama3",
+		Messages: []golloom.Message{{Role: "user", Content: "loop"}},
+	}
+
+	_, err := srv.Client().RunWithTools(context.Background(), req, registry, 2)
+	if !errors.Is(err, golloom.ErrToolStepLimit) {
+		t.Fatalf("err = %v, want ErrToolStepLimit", err)
+	}
+
+	if got := len(srv.RequestsTo("/api/chat")); got != 2 {
+		t.Errorf("chat requests = %d, want 2", got)
+	}
+}
+
+func TestToolCallArgumentsInto(t *testing.T) {
+	call := golloom.ToolCallFunction{
+		Name: "weather",
+		Arguments: map[string]interface{}{
+			"city": "Manila",
+			"days": float64(3),
+		},
+	}
+
+	var args struct {
+		City string `json:"city"`
+		Days int    `json:"days"`
+	}
+
+	if err := call.ArgumentsInto(&args); err != nil {
+		t.Fatal(err)
+	}
+
+	if args.City != "Manila" || args.Days != 3 {
+		t.Errorf("args = %+v", args)
+	}
+}
@@ -0,0 +1,228 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloom
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// ErrDigestRejected is returned by UploadFile when the server refuses a blob because its
+// content does not match the digest, typically because the file changed while being uploaded.
+var ErrDigestRejected = errors.New("server rejected blob digest")
+
+// progressInterval is the number of bytes between two progress events reported while
+// a file is hashed or uploaded.
+const progressInterval = 1 << 20
+
+// UploadFile uploads the file at path as a blob and returns its "sha256:" digest.
+// See UploadFileProgress for details.
+func (c *Client) UploadFile(
+	ctx context.Context,
+	path string,
+) (string, error) {
+	return c.UploadFileProgress(ctx, path, nil)
+}
+
+// UploadFileProgress uploads the file at path as a blob, computing its digest locally rather than
+// trusting the caller. The file is hashed first; if CheckBlobExists reports that the server already
+// has the blob, nothing is uploaded. Otherwise the file is streamed to the server, which verifies the
+// digest again. Progress is reported to fn while hashing ("hashing <name>") and uploading
+// ("uploading <name>"), followed by "using existing blob" or "success".
+// Parameters:
+//   - ctx: A context to control request lifetime (e.g., cancellation).
+//   - path: The path of the file to upload.
+//   - fn: An optional callback receiving each event; a non-nil error aborts the upload.
+//
+// Returns:
+//   - The "sha256:" digest of the file, usable in CreateModelRequest.Files and Adapters.
+//   - An error wrapping ErrDigestRejected if the server rejects the digest, or any I/O or request error.
+func (c *Client) UploadFileProgress(
+	ctx context.Context,
+	path string,
+	fn ProgressFunc,
+) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	if info.IsDir() {
+		return "", fmt.Errorf("%s is a directory", path)
+	}
+
+	name := filepath.Base(path)
+	size := info.Size()
+
+	hasher := sha256.New()
+	hashing := &progressReader{
+		ctx:     ctx,
+		reader:  f,
+		total:   size,
+		status:  "hashing " + name,
+		tracker: newProgressTracker(),
+		fn:      fn,
+	}
+
+	if _, err := io.Copy(hasher, hashing); err != nil {
+		return "", err
+	}
+
+	if err := hashing.report(); err != nil {
+		return "", err
+	}
+
+	digest := "sha256:" + hex.EncodeToString(hasher.Sum(nil))
+
+	exists, err := c.CheckBlobExists(ctx, digest)
+	if err != nil {
+		return "", err
+	}
+
+	if exists {
+		return digest, emitProgress(fn, &ProgressEvent{
+			Status: "using existing blob",
+			Digest: digest,
+		})
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	uploading := &progressReader{
+		ctx:     ctx,
+		reader:  f,
+		total:   size,
+		status:  "uploading " + name,
+		digest:  digest,
+		tracker: newProgressTracker(),
+		fn:      fn,
+	}
+
+	if err := c.pushBlob(ctx, digest, uploading, size); err != nil {
+		if uploading.err != nil {
+			return "", uploading.err
+		}
+
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest {
+			return "", fmt.Errorf(
+				"%w: %s for %s: %w",
+				ErrDigestRejected,
+				digest,
+				path,
+				err,
+			)
+		}
+
+		return "", err
+	}
+
+	if err := uploading.report(); err != nil {
+		return "", err
+	}
+
+	return digest, emitProgress(fn, &ProgressEvent{
+		Status: "success",
+		Digest: digest,
+	})
+}
+
+// emitProgress passes ev to fn, if set.
+func emitProgress(fn ProgressFunc, ev *ProgressEvent) error {
+	if fn == nil {
+		return nil
+	}
+
+	return fn(ev)
+}
+
+// progressReader reports the bytes read through it as ProgressEvents, at most once per
+// progressInterval bytes. An error returned by the callback aborts reading and is kept in err.
+type progressReader struct {
+	ctx     context.Context
+	reader  io.Reader
+	total   int64
+	status  string
+	digest  string
+	tracker *progressTracker
+	fn      ProgressFunc
+
+	completed int64
+	reported  int64
+	err       error
+}
+
+// Read reads from the underlying reader and reports progress when due.
+func (r *progressReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	n, err := r.reader.Read(p)
+	r.completed += int64(n)
+
+	if r.completed-r.reported >= progressInterval {
+		if reportErr := r.report(); reportErr != nil {
+			return n, reportErr
+		}
+	}
+
+	return n, err
+}
+
+// report emits a progress event for the bytes read so far, unless they were already reported.
+func (r *progressReader) report() error {
+	if r.fn == nil || (r.reported == r.completed && r.completed > 0) {
+		return nil
+	}
+
+	r.reported = r.completed
+	ev := &ProgressEvent{
+		Status:    r.status,
+		Digest:    r.digest,
+		Total:     r.total,
+		Completed: r.completed,
+	}
+
+	r.tracker.observeLayer(ev, r.status)
+	if err := r.fn(ev); err != nil {
+		r.err = err
+		return err
+	}
+
+	return nil
+}
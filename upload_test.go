@@ -0,0 +1,200 @@
+/*
+ * Copyright 2025 Nathanne Isip
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package golloom_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nthnn/golloom"
+	"github.com/nthnn/golloom/golloomtest"
+)
+
+// writeWeights writes size bytes of patterned data to a file named model.gguf in a temporary
+// directory and returns its path and content.
+func writeWeights(t *testing.T, size int) (string, []byte) {
+	t.Helper()
+
+	data := bytes.Repeat([]byte("golloom!"), size/8)
+	path := filepath.Join(t.TempDir(), "model.gguf")
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path, data
+}
+
+// blobPosts counts the blob uploads received by srv.
+func blobPosts(srv *golloomtest.Server) int {
+	n := 0
+	for _, r := range srv.Requests() {
+		if r.Method == http.MethodPost && strings.HasPrefix(r.Path, "/api/blobs/") {
+			n++
+		}
+	}
+
+	return n
+}
+
+func TestUploadFileProgress(t *testing.T) {
+	srv := golloomtest.NewServer()
+	defer srv.Close()
+
+	path, data := writeWeights(t, 3<<20)
+	sum := sha256.Sum256(data)
+	want := "sha256:" + hex.EncodeToString(sum[:])
+
+	var events []golloom.ProgressEvent
+	digest, err := srv.Client().UploadFileProgress(context.Background(), path, func(ev *golloom.ProgressEvent) error {
+		events = append(events, *ev)
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if digest != want {
+		t.Errorf("digest = %s, want %s", digest, want)
+	}
+
+	if stored, ok := srv.Blob(digest); !ok || !bytes.Equal(stored, data) {
+		t.Error("the server does not hold the uploaded blob")
+	}
+
+	completed := map[string]int64{}
+	for _, ev := range events[:len(events)-1] {
+		if ev.Total != int64(len(data)) || ev.Completed < completed[ev.Status] {
+			t.Errorf("event %+v does not advance towards the file size", ev)
+		}
+
+		completed[ev.Status] = ev.Completed
+	}
+
+	for _, status := range []string{"hashing model.gguf", "uploading model.gguf"} {
+		if completed[status] != int64(len(data)) {
+			t.Errorf("%q reached %d of %d bytes", status, completed[status], len(data))
+		}
+	}
+
+	if last := events[len(events)-1]; last.Status != "success" || last.Digest != digest {
+		t.Errorf("last event = %+v, want success with the digest", last)
+	}
+}
+
+func TestUploadFileSkipsExistingBlob(t *testing.T) {
+	srv := golloomtest.NewServer()
+	defer srv.Close()
+
+	path, _ := writeWeights(t, 1024)
+	client := srv.Client()
+
+	first, err := client.UploadFile(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var last string
+	second, err := client.UploadFileProgress(context.Background(), path, func(ev *golloom.ProgressEvent) error {
+		last = ev.Status
+		return nil
+	})
+
+	if err != nil || second != first {
+		t.Fatalf("second upload = %s, %v; want %s", second, err, first)
+	}
+
+	if last != "using existing blob" {
+		t.Errorf("last status = %q, want using existing blob", last)
+	}
+
+	if got := blobPosts(srv); got != 1 {
+		t.Errorf("blob uploads = %d, want 1", got)
+	}
+}
+
+func TestUploadFileDigestRejected(t *testing.T) {
+	srv := golloomtest.NewServer()
+	defer srv.Close()
+
+	path, data := writeWeights(t, 3<<20)
+
+	changed := false
+	_, err := srv.Client(golloom.WithRetryPolicy(nil)).UploadFileProgress(
+		context.Background(),
+		path,
+		func(ev *golloom.ProgressEvent) error {
+			if changed || !strings.HasPrefix(ev.Status, "uploading") {
+				return nil
+			}
+
+			changed = true
+
+			f, err := os.OpenFile(path, os.O_WRONLY, 0)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			_, err = f.WriteAt([]byte("X"), int64(len(data)-1))
+			return err
+		},
+	)
+
+	if !errors.Is(err, golloom.ErrDigestRejected) {
+		t.Fatalf("err = %v, want ErrDigestRejected for a file changed during the upload", err)
+	}
+
+	var apiErr *golloom.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("err = %v, want it to wrap the server's 400", err)
+	}
+}
+
+func TestUploadFileErrors(t *testing.T) {
+	srv := golloomtest.NewServer()
+	defer srv.Close()
+
+	path, _ := writeWeights(t, 3<<20)
+	stop := errors.New("stop")
+
+	_, err := srv.Client().UploadFileProgress(context.Background(), path, func(ev *golloom.ProgressEvent) error {
+		if strings.HasPrefix(ev.Status, "uploading") {
+			return stop
+		}
+
+		return nil
+	})
+
+	if !errors.Is(err, stop) {
+		t.Fatalf("err = %v, want the callback's error", err)
+	}
+
+	if _, err := srv.Client().UploadFile(context.Background(), filepath.Dir(path)); err == nil {
+		t.Error("uploading a directory succeeded")
+	}
+}
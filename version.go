@@ -20,7 +20,6 @@ package golloom
 import (
 	"context"
 	"encoding/json"
-	"net/http"
 	"net/url"
 )
 
@@ -39,19 +38,23 @@ func (c *Client) Version(ctx context.Context) (*Version, error) {
 		Path: "/api/version",
 	}
 
-	u := c.BaseURL.ResolveReference(rel)
-	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	u := c.resolve(rel)
+	req, err := c.newRequest(ctx, "GET", u.String(), nil)
 
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.doBuffered(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+
 	var verResp Version
 	if err := json.NewDecoder(resp.Body).Decode(&verResp); err != nil {
 		return nil, err